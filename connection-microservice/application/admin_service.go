@@ -0,0 +1,216 @@
+package application
+
+import (
+	"connection-microservice/metrics"
+	"connection-microservice/model"
+	"connection-microservice/startup/config"
+	"context"
+	"fmt"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
+)
+
+// AdminService lets moderators inspect and repair the graph. Every method requires the admin role and
+// bypasses the block and ownership checks regular users are subject to.
+type AdminService struct {
+	store           model.ConnectionStore
+	blockStore      model.BlockStore
+	impressionStore model.ImpressionStore
+	suggestionCache *SuggestionCache
+	config          *config.Config
+}
+
+func NewAdminService(store model.ConnectionStore, blockStore model.BlockStore, impressionStore model.ImpressionStore, suggestionCache *SuggestionCache, c *config.Config) *AdminService {
+	return &AdminService{
+		store:           store,
+		blockStore:      blockStore,
+		impressionStore: impressionStore,
+		suggestionCache: suggestionCache,
+		config:          c,
+	}
+}
+
+func (service *AdminService) ForceRemoveConnection(ctx context.Context, userId string, connectedUserId string) error {
+	Log.Info("Force removing connection of users with id1: " + userId + " , id2: " + connectedUserId)
+
+	span := tracer.StartSpanFromContext(ctx, "ForceRemoveConnection")
+	defer span.Finish()
+	if err := authorizeRole(ctx, service.config.AdminRole); err != nil {
+		return err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	err := service.store.DeleteConnection(ctx, userId, connectedUserId)
+	if err != nil {
+		return domainError(err)
+	}
+	service.suggestionCache.Invalidate(userId, connectedUserId)
+	metrics.ConnectionEvent(metrics.ConnectionDeleted)
+	recordConnectionEvent(ConnectionForceRemoved, userId, connectedUserId)
+	return nil
+}
+
+func (service *AdminService) ForceRemoveBlock(ctx context.Context, userId string, blockedUserId string) error {
+	Log.Info("Force removing block of user with id: " + blockedUserId + " by user with id: " + userId)
+
+	span := tracer.StartSpanFromContext(ctx, "ForceRemoveBlock")
+	defer span.Finish()
+	if err := authorizeRole(ctx, service.config.AdminRole); err != nil {
+		return err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	block := model.Block{UserId: userId, BlockedUserId: blockedUserId}
+	blocked, err := service.blockStore.IsBlocked(ctx, block)
+	if err != nil {
+		return err
+	}
+	if !blocked {
+		return ErrNoBlock
+	}
+	err = service.blockStore.UnblockUser(ctx, block)
+	if err != nil {
+		return err
+	}
+	service.suggestionCache.Invalidate(userId, blockedUserId)
+	metrics.BlockEvent(metrics.UserUnblocked)
+	recordConnectionEvent(BlockForceRemoved, userId, blockedUserId)
+	return nil
+}
+
+func (service *AdminService) GetAllBlocks(ctx context.Context, page model.PageRequest) (*model.BlockPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetAllBlocks")
+	defer span.Finish()
+	if err := authorizeRole(ctx, service.config.AdminRole); err != nil {
+		return nil, err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	return service.blockStore.GetAllBlocksPage(ctx, newPageRequest(service.config, page.Cursor, page.Size))
+}
+
+// GetNeighbourhood returns the first config.MaxPageSize items of each relationship list of the user, in the
+// order of the paged store queries.
+func (service *AdminService) GetNeighbourhood(ctx context.Context, userId string) (*model.Neighbourhood, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetNeighbourhood")
+	defer span.Finish()
+	if err := authorizeRole(ctx, service.config.AdminRole); err != nil {
+		return nil, err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	page := newPageRequest(service.config, "", service.config.MaxPageSize)
+	followings, err := service.store.GetFollowingsPage(ctx, userId, page)
+	if err != nil {
+		return nil, err
+	}
+	followers, err := service.store.GetFollowersPage(ctx, userId, page)
+	if err != nil {
+		return nil, err
+	}
+	sentRequests, err := service.store.GetAllPendingConnectionsPage(ctx, userId, page)
+	if err != nil {
+		return nil, err
+	}
+	receivedRequests, err := service.store.GetAllRequestConnectionsPage(ctx, userId, page)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := service.blockStore.GetBlockedPage(ctx, userId, page)
+	if err != nil {
+		return nil, err
+	}
+	blockedBy, err := service.blockStore.GetBlockedByPage(ctx, userId, page)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Neighbourhood{
+		UserId:           userId,
+		Followings:       followings.Connections,
+		Followers:        followers.Connections,
+		SentRequests:     sentRequests.Connections,
+		ReceivedRequests: receivedRequests.Connections,
+		Blocked:          blocked.UserIds,
+		BlockedBy:        blockedBy.UserIds,
+	}, nil
+}
+
+// getNeighbourhood returns every relationship of the user, however many there are.
+func (service *AdminService) getNeighbourhood(ctx context.Context, userId string) (*model.Neighbourhood, error) {
+	neighbourhood := &model.Neighbourhood{UserId: userId}
+	var err error
+	if neighbourhood.Followings, err = service.store.GetFollowings(ctx, userId); err != nil {
+		return nil, err
+	}
+	if neighbourhood.Followers, err = service.store.GetFollowers(ctx, userId); err != nil {
+		return nil, err
+	}
+	if neighbourhood.SentRequests, err = service.store.GetAllPendingConnectionsByUserId(ctx, userId); err != nil {
+		return nil, err
+	}
+	if neighbourhood.ReceivedRequests, err = service.store.GetAllRequestConnectionsByUserId(ctx, userId); err != nil {
+		return nil, err
+	}
+	if neighbourhood.Blocked, err = service.blockStore.GetBlocked(ctx, userId); err != nil {
+		return nil, err
+	}
+	if neighbourhood.BlockedBy, err = service.blockStore.GetBlockedBy(ctx, userId); err != nil {
+		return nil, err
+	}
+	return neighbourhood, nil
+}
+
+// BanUser removes every relationship of the user and returns how many were removed. Users that were related
+// to the banned user get their suggestions recomputed.
+func (service *AdminService) BanUser(ctx context.Context, userId string) (int64, error) {
+	Log.Info("Removing all relationships of banned user with id: " + userId)
+
+	span := tracer.StartSpanFromContext(ctx, "BanUser")
+	defer span.Finish()
+	if err := authorizeRole(ctx, service.config.AdminRole); err != nil {
+		return 0, err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	neighbourhood, err := service.getNeighbourhood(ctx, userId)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := service.store.DeleteUserEdges(ctx, userId)
+	if err != nil {
+		Log.Error("Error while removing relationships of banned user. Error: " + err.Error())
+		return 0, err
+	}
+
+	service.suggestionCache.Invalidate(userId)
+	for _, connections := range [][]*model.Connection{neighbourhood.Followings, neighbourhood.SentRequests} {
+		for _, connection := range connections {
+			service.suggestionCache.Invalidate(connection.ConnectedUserId)
+		}
+	}
+	for _, connections := range [][]*model.Connection{neighbourhood.Followers, neighbourhood.ReceivedRequests} {
+		for _, connection := range connections {
+			service.suggestionCache.Invalidate(connection.UserId)
+		}
+	}
+	service.suggestionCache.Invalidate(neighbourhood.Blocked...)
+	service.suggestionCache.Invalidate(neighbourhood.BlockedBy...)
+
+	Log.Info(fmt.Sprintf("Removed %d relationships of banned user with id: %s", removed, userId))
+	return removed, nil
+}
+
+// GetSuggestionExperimentStats returns acceptance rates per variant. An empty experiment selects the
+// running one.
+func (service *AdminService) GetSuggestionExperimentStats(ctx context.Context, experiment string) ([]*model.VariantStats, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetSuggestionExperimentStats")
+	defer span.Finish()
+	if err := authorizeRole(ctx, service.config.AdminRole); err != nil {
+		return nil, err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	if experiment == "" {
+		experiment = service.config.SuggestionExperiment
+	}
+	return service.impressionStore.GetVariantStats(ctx, experiment)
+}
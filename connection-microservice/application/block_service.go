@@ -1,6 +1,7 @@
 package application
 
 import (
+	"connection-microservice/metrics"
 	"connection-microservice/model"
 	"connection-microservice/startup/config"
 	"context"
@@ -10,43 +11,44 @@ import (
 type BlockService struct {
 	store           model.BlockStore
 	connectionStore model.ConnectionStore
+	suggestionCache *SuggestionCache
 	config          *config.Config
 }
 
-func NewBlockService(store model.BlockStore, connectionStore model.ConnectionStore, c *config.Config) *BlockService {
+func NewBlockService(store model.BlockStore, connectionStore model.ConnectionStore, suggestionCache *SuggestionCache, c *config.Config) *BlockService {
 	return &BlockService{
 		store:           store,
 		connectionStore: connectionStore,
+		suggestionCache: suggestionCache,
 		config:          c,
 	}
 }
 
-func (service *BlockService) BlockUser(ctx context.Context, userId string, blockedUserId string) error {
+// BlockUser blocks the user and severs their connections in both directions. It returns the removed connections.
+func (service *BlockService) BlockUser(ctx context.Context, userId string, blockedUserId string) ([]*model.Connection, error) {
 	Log.Info("User with id: " + userId + " blocks user with id: " + blockedUserId)
 
 	span := tracer.StartSpanFromContext(ctx, "BlockUser")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	err := service.store.BlockUser(ctx, model.Block{UserId: userId, BlockedUserId: blockedUserId})
+	removed, err := service.store.BlockAndSever(ctx, model.Block{UserId: userId, BlockedUserId: blockedUserId})
 	if err != nil {
 		Log.Error("Error on blocking user. Error: " + err.Error())
-		return err
-	}
-
-	err = service.connectionStore.DeleteConnection(ctx, userId, blockedUserId)
-	if err != nil {
-		Log.Error("Error deleting connection after blocking user. Error: " + err.Error())
-		return err
+		return nil, err
 	}
+	service.suggestionCache.Invalidate(userId, blockedUserId)
+	metrics.BlockEvent(metrics.UserBlocked)
 
-	err = service.connectionStore.DeleteConnection(ctx, blockedUserId, userId)
-	if err != nil {
-		Log.Error("Error deleting connection after blocking user. Error: " + err.Error())
-		return err
+	for _, connection := range removed {
+		metrics.ConnectionEvent(metrics.ConnectionDeleted)
+		Log.Info("Removed connection of users with id1: " + connection.UserId + " , id2: " + connection.ConnectedUserId + " after blocking")
 	}
 
-	return nil
+	return removed, nil
 }
 
 func (service *BlockService) UnblockUser(ctx context.Context, userId string, blockedUserId string) error {
@@ -54,6 +56,9 @@ func (service *BlockService) UnblockUser(ctx context.Context, userId string, blo
 
 	span := tracer.StartSpanFromContext(ctx, "BlockUser")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	err := service.store.UnblockUser(ctx, model.Block{UserId: userId, BlockedUserId: blockedUserId})
@@ -61,6 +66,8 @@ func (service *BlockService) UnblockUser(ctx context.Context, userId string, blo
 		Log.Error("Error on unblocking user. Error: " + err.Error())
 		return err
 	}
+	service.suggestionCache.Invalidate(userId, blockedUserId)
+	metrics.BlockEvent(metrics.UserUnblocked)
 	return nil
 }
 
@@ -106,24 +113,30 @@ func (service *BlockService) IsBlockedAny(ctx context.Context, userId string, bl
 	return blocked, nil
 }
 
-func (service *BlockService) GetBlocked(ctx context.Context, userId string) ([]string, error) {
+func (service *BlockService) GetBlocked(ctx context.Context, userId string, page model.PageRequest) (*model.UserIdPage, error) {
 	Log.Info("Get blocked of user with id: " + userId)
 
 	span := tracer.StartSpanFromContext(ctx, "GetBlocked")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetBlocked(ctx, userId)
+	return service.store.GetBlockedPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
-func (service *BlockService) GetBlockedBy(ctx context.Context, userId string) ([]string, error) {
+func (service *BlockService) GetBlockedBy(ctx context.Context, userId string, page model.PageRequest) (*model.UserIdPage, error) {
 	Log.Info("Get users blocked by user with id: " + userId)
 
 	span := tracer.StartSpanFromContext(ctx, "GetBlockedBy")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetBlockedBy(ctx, userId)
+	return service.store.GetBlockedByPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
 func (service *BlockService) GetBlockedAny(ctx context.Context, userId string) ([]string, error) {
@@ -131,8 +144,17 @@ func (service *BlockService) GetBlockedAny(ctx context.Context, userId string) (
 
 	span := tracer.StartSpanFromContext(ctx, "GetBlockedAny")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
+	return service.getBlockedAny(ctx, userId)
+}
+
+// getBlockedAny returns users blocked by or blocking the user without checking the caller, for services that
+// exclude them from their results.
+func (service *BlockService) getBlockedAny(ctx context.Context, userId string) ([]string, error) {
 	blocked, err := service.store.GetBlocked(ctx, userId)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,49 @@
+package application
+
+import "context"
+
+// Caller is the authenticated user a request is made by.
+type Caller struct {
+	UserId string
+	Role   string
+}
+
+type callerKey struct{}
+
+func ContextWithCaller(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func CallerFromContext(ctx context.Context) (*Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(*Caller)
+	return caller, ok && caller != nil
+}
+
+// authorizeActingUser checks that the caller is one of the given users, the party a request acts for.
+// It must run before the context is replaced with a span context, which drops the caller.
+func authorizeActingUser(ctx context.Context, userIds ...string) error {
+	caller, ok := CallerFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	for _, userId := range userIds {
+		if caller.UserId == userId {
+			return nil
+		}
+	}
+	Log.Warn("User with id: " + caller.UserId + " is not the acting user")
+	return ErrNotActingUser
+}
+
+// authorizeRole checks that the caller has the role.
+func authorizeRole(ctx context.Context, role string) error {
+	caller, ok := CallerFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if caller.Role != role {
+		Log.Warn("User with id: " + caller.UserId + " does not have role: " + role)
+		return ErrNotAdmin
+	}
+	return nil
+}
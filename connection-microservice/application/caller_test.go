@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAuthorizeActingUser(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  *Caller
+		userIds []string
+		wantErr error
+	}{
+		{name: "no caller", userIds: []string{"a"}, wantErr: ErrUnauthenticated},
+		{name: "acting user", caller: &Caller{UserId: "a"}, userIds: []string{"a"}},
+		{name: "either party", caller: &Caller{UserId: "b"}, userIds: []string{"a", "b"}},
+		{name: "other user", caller: &Caller{UserId: "c"}, userIds: []string{"a", "b"}, wantErr: ErrNotActingUser},
+		{name: "admin is not the acting user", caller: &Caller{UserId: "c", Role: "ADMIN"}, userIds: []string{"a"}, wantErr: ErrNotActingUser},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.caller != nil {
+				ctx = ContextWithCaller(ctx, test.caller)
+			}
+			if err := authorizeActingUser(ctx, test.userIds...); err != test.wantErr {
+				t.Fatalf("got %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthorizeRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		caller  *Caller
+		wantErr error
+	}{
+		{name: "no caller", wantErr: ErrUnauthenticated},
+		{name: "admin", caller: &Caller{UserId: "a", Role: "ADMIN"}},
+		{name: "user", caller: &Caller{UserId: "a", Role: "USER"}, wantErr: ErrNotAdmin},
+		{name: "no role", caller: &Caller{UserId: "a"}, wantErr: ErrNotAdmin},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.caller != nil {
+				ctx = ContextWithCaller(ctx, test.caller)
+			}
+			if err := authorizeRole(ctx, "ADMIN"); err != test.wantErr {
+				t.Fatalf("got %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNilCallerIsUnauthenticated(t *testing.T) {
+	if _, ok := CallerFromContext(ContextWithCaller(context.Background(), nil)); ok {
+		t.Fatal("a nil caller must not count as authenticated")
+	}
+}
@@ -0,0 +1,209 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"connection-microservice/startup/config"
+	"context"
+	"fmt"
+	userService "github.com/XWS-BSEP-TIM1-2022/dislinkt/util/proto/user"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
+	"sync"
+	"time"
+)
+
+const ColdStartStrategy = "cold_start"
+
+// ColdStart suggests users without looking at the user's network, mixing the most followed users, recently
+// active users and a seeded random sample in the proportions of config.ColdStartPopular, ColdStartActive and
+// ColdStartRandom. It fills up suggestion lists of users with few or no followings.
+type ColdStart struct {
+	store      model.ConnectionStore
+	userClient userService.UserServiceClient
+	config     *config.Config
+	lock       sync.Mutex
+	pools      *coldStartPools
+}
+
+// coldStartPools hold up to config.ColdStartPoolSize users of every source for all users, so a suggestion only
+// costs a lookup of the user's exclusions. They are reloaded once they are older than config.SuggestionRefresh.
+type coldStartPools struct {
+	loadedAt time.Time
+	popular  []*model.SuggestionCandidate
+	active   []string
+	random   []string
+	lock     sync.Mutex
+	private  map[string]bool
+}
+
+func NewColdStart(store model.ConnectionStore, userClient userService.UserServiceClient, c *config.Config) *ColdStart {
+	return &ColdStart{
+		store:      store,
+		userClient: userClient,
+		config:     c,
+	}
+}
+
+type coldStartSource struct {
+	weight      int
+	suggestions []*model.Suggestion
+}
+
+// Suggest returns up to limit suggestions, leaving out users skip returns true for, users suggestion queries
+// exclude for the user and, with excludePrivate, private users.
+func (coldStart *ColdStart) Suggest(ctx context.Context, userId string, limit int, excludePrivate bool, skip func(userId string) bool) ([]*model.Suggestion, error) {
+	span := tracer.StartSpanFromContext(ctx, "ColdStart")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	pools, err := coldStart.loadPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exclusions, err := coldStart.store.GetSuggestionExclusions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	excluded := map[string]bool{userId: true}
+	for _, user := range exclusions {
+		excluded[user] = true
+	}
+
+	sources := []*coldStartSource{
+		{weight: coldStart.config.ColdStartPopular},
+		{weight: coldStart.config.ColdStartActive},
+		{weight: coldStart.config.ColdStartRandom},
+	}
+	for _, user := range pools.popular {
+		sources[0].suggestions = append(sources[0].suggestions, &model.Suggestion{
+			UserId:      user.UserId,
+			Reason:      model.SuggestionReasonPopular,
+			Explanation: fmt.Sprintf("Followed by %d people", user.FollowerCount),
+			Strategy:    ColdStartStrategy,
+		})
+	}
+	for _, user := range pools.active {
+		sources[1].suggestions = append(sources[1].suggestions, &model.Suggestion{
+			UserId:      user,
+			Reason:      model.SuggestionReasonRecentlyActive,
+			Explanation: "Recently active",
+			Strategy:    ColdStartStrategy,
+		})
+	}
+	// the random pool is reshuffled so users see a different sample on every call
+	random := model.SampleUserIds(append([]string{}, pools.random...), len(pools.random), coldStart.seed())
+	for _, user := range random {
+		sources[2].suggestions = append(sources[2].suggestions, &model.Suggestion{
+			UserId:      user,
+			Reason:      model.SuggestionReasonSuggestedForYou,
+			Explanation: "Suggested for you",
+			Strategy:    ColdStartStrategy,
+		})
+	}
+
+	totalWeight := 0
+	for _, source := range sources {
+		if source.weight > 0 {
+			totalWeight += source.weight
+		}
+	}
+
+	seen := map[string]bool{}
+	var suggestions []*model.Suggestion
+	pick := func(source *coldStartSource, quota int) {
+		for _, suggestion := range source.suggestions {
+			if len(suggestions) == limit || quota == 0 {
+				return
+			}
+			if seen[suggestion.UserId] || excluded[suggestion.UserId] || skip(suggestion.UserId) {
+				continue
+			}
+			seen[suggestion.UserId] = true
+			if excludePrivate && pools.isPrivate(ctx, coldStart.userClient, suggestion.UserId) {
+				continue
+			}
+			suggestions = append(suggestions, suggestion)
+			quota--
+		}
+	}
+
+	// every source first gets its share, rounded up, then whatever is left fills up the list
+	if totalWeight > 0 {
+		for _, source := range sources {
+			if source.weight > 0 {
+				pick(source, (limit*source.weight+totalWeight-1)/totalWeight)
+			}
+		}
+	}
+	for _, source := range sources {
+		if source.weight > 0 || totalWeight == 0 {
+			pick(source, limit)
+		}
+	}
+	return suggestions, nil
+}
+
+// loadPools returns the current pools, reloading them when they are older than config.SuggestionRefresh.
+// Concurrent callers wait for a single reload.
+func (coldStart *ColdStart) loadPools(ctx context.Context) (*coldStartPools, error) {
+	coldStart.lock.Lock()
+	defer coldStart.lock.Unlock()
+
+	now := time.Now()
+	if coldStart.pools != nil && now.Sub(coldStart.pools.loadedAt) < coldStart.config.SuggestionRefresh {
+		return coldStart.pools, nil
+	}
+
+	size := coldStart.config.ColdStartPoolSize
+	popular, err := coldStart.store.GetMostFollowedUsers(ctx, size)
+	if err != nil {
+		return nil, err
+	}
+	active, err := coldStart.store.GetRecentlyActiveUsers(ctx, size)
+	if err != nil {
+		return nil, err
+	}
+	random, err := coldStart.store.GetRandomUsers(ctx, size, coldStart.seed())
+	if err != nil {
+		return nil, err
+	}
+
+	coldStart.pools = &coldStartPools{
+		loadedAt: now,
+		popular:  popular,
+		active:   active,
+		random:   random,
+		private:  map[string]bool{},
+	}
+	return coldStart.pools, nil
+}
+
+// seed returns config.ColdStartSeed or, when it is zero, a seed that changes every call.
+func (coldStart *ColdStart) seed() int64 {
+	seed := int64(coldStart.config.ColdStartSeed)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return seed
+}
+
+// isPrivate treats users whose privacy can not be checked as private. Answers are kept with the pools, so every
+// user costs at most one call to the user service per reload.
+func (pools *coldStartPools) isPrivate(ctx context.Context, userClient userService.UserServiceClient, userId string) bool {
+	pools.lock.Lock()
+	isPrivate, found := pools.private[userId]
+	pools.lock.Unlock()
+	if found {
+		return isPrivate
+	}
+
+	response, err := userClient.IsUserPrivateRequest(ctx, &userService.UserIdRequest{UserId: userId})
+	if err != nil {
+		Log.Warn("Cant check if user with id: " + userId + " is private. Error: " + err.Error())
+		return true
+	}
+
+	pools.lock.Lock()
+	pools.private[userId] = response.IsPrivate
+	pools.lock.Unlock()
+	return response.IsPrivate
+}
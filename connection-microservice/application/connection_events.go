@@ -0,0 +1,35 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ConnectionRequestRejected  = "connection_request_rejected"
+	ConnectionRequestWithdrawn = "connection_request_withdrawn"
+	SuggestionAccepted         = "suggestion_accepted"
+	SuggestionDismissed        = "suggestion_dismissed"
+	ConnectionForceRemoved     = "connection_force_removed"
+	BlockForceRemoved          = "block_force_removed"
+)
+
+// recordConnectionEvent writes a structured log entry that analytics picks up by its event field.
+func recordConnectionEvent(event string, userId string, connectedUserId string) {
+	Log.WithFields(logrus.Fields{
+		"event":           event,
+		"userId":          userId,
+		"connectedUserId": connectedUserId,
+	}).Info("Connection event: " + event)
+}
+
+// recordSuggestionEvent writes a structured log entry for a connection attributed to a suggestion.
+func recordSuggestionEvent(event string, impression *model.SuggestionImpression) {
+	Log.WithFields(logrus.Fields{
+		"event":           event,
+		"userId":          impression.UserId,
+		"connectedUserId": impression.SuggestedUserId,
+		"experiment":      impression.Experiment,
+		"variant":         impression.Variant,
+	}).Info("Connection event: " + event)
+}
@@ -0,0 +1,63 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"connection-microservice/startup/config"
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConnectionExpiryWorker periodically expires pending connection requests older than config.PendingConnectionTTL
+// and removes expired suggestion dismissals.
+type ConnectionExpiryWorker struct {
+	*PeriodicWorker
+	store           model.ConnectionStore
+	dismissalStore  model.DismissalStore
+	suggestionCache *SuggestionCache
+	config          *config.Config
+}
+
+func NewConnectionExpiryWorker(store model.ConnectionStore, dismissalStore model.DismissalStore, suggestionCache *SuggestionCache, c *config.Config) *ConnectionExpiryWorker {
+	worker := &ConnectionExpiryWorker{
+		store:           store,
+		dismissalStore:  dismissalStore,
+		suggestionCache: suggestionCache,
+		config:          c,
+	}
+	worker.PeriodicWorker = NewPeriodicWorker(c.ExpiryInterval, func(ctx context.Context) {
+		worker.ExpirePendingConnections(ctx)
+		worker.DeleteExpiredDismissals(ctx)
+	})
+	return worker
+}
+
+func (worker *ConnectionExpiryWorker) ExpirePendingConnections(ctx context.Context) {
+	start := time.Now()
+	requestedBefore := start.Add(-worker.config.PendingConnectionTTL).UTC()
+
+	expired, err := worker.store.ExpirePendingConnections(ctx, requestedBefore)
+	if err != nil {
+		Log.Error("Error while expiring pending connections. Error: " + err.Error())
+		return
+	}
+
+	// requesters may be suggested the users they asked again
+	for _, connection := range expired {
+		worker.suggestionCache.Invalidate(connection.UserId)
+	}
+
+	Log.Info(fmt.Sprintf("Expired %d pending connections requested before %s in %s", len(expired), requestedBefore.Format(time.RFC3339), time.Since(start)))
+}
+
+func (worker *ConnectionExpiryWorker) DeleteExpiredDismissals(ctx context.Context) {
+	start := time.Now()
+
+	deleted, err := worker.dismissalStore.DeleteExpiredDismissals(ctx, start.UTC())
+	if err != nil {
+		Log.Error("Error while deleting expired dismissals. Error: " + err.Error())
+		return
+	}
+
+	Log.Info(fmt.Sprintf("Deleted %d expired dismissals in %s", deleted, time.Since(start)))
+}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"connection-microservice/metrics"
 	"connection-microservice/model"
 	"connection-microservice/startup/config"
 	"context"
@@ -10,23 +11,41 @@ import (
 	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/services"
 	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
 	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type ConnectionService struct {
-	store        model.ConnectionStore
-	userClient   userService.UserServiceClient
-	config       *config.Config
-	blockService *BlockService
+	store           model.ConnectionStore
+	impressionStore model.ImpressionStore
+	dismissalStore  model.DismissalStore
+	userClient      userService.UserServiceClient
+	config          *config.Config
+	blockService    *BlockService
+	strategies      map[string]SuggestionStrategy
+	experiment      *SuggestionExperiment
+	coldStart       *ColdStart
+	suggestionCache *SuggestionCache
 }
 
 var Log = logrus.New()
 
-func NewConnectionService(store model.ConnectionStore, c *config.Config, blockService *BlockService) *ConnectionService {
+const suggestionsLimit = 15
+
+func NewConnectionService(store model.ConnectionStore, impressionStore model.ImpressionStore, dismissalStore model.DismissalStore, suggestionCache *SuggestionCache, c *config.Config, blockService *BlockService) *ConnectionService {
+	userClient := services.NewUserClient(fmt.Sprintf("%s:%s", c.UserServiceHost, c.UserServicePort))
 	return &ConnectionService{
-		store:        store,
-		blockService: blockService,
-		config:       c,
-		userClient:   services.NewUserClient(fmt.Sprintf("%s:%s", c.UserServiceHost, c.UserServicePort))}
+		store:           store,
+		impressionStore: impressionStore,
+		dismissalStore:  dismissalStore,
+		blockService:    blockService,
+		config:          c,
+		strategies:      NewSuggestionStrategies(store, c.SuggestionPoolSize),
+		experiment:      NewSuggestionExperiment(c.SuggestionExperiment, c.SuggestionVariants),
+		coldStart:       NewColdStart(store, userClient, c),
+		suggestionCache: suggestionCache,
+		userClient:      userClient}
 }
 
 func (service *ConnectionService) CreateConnection(ctx context.Context, connection *model.Connection) (*model.Connection, error) {
@@ -34,13 +53,37 @@ func (service *ConnectionService) CreateConnection(ctx context.Context, connecti
 
 	span := tracer.StartSpanFromContext(ctx, "CreateConnection")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, connection.UserId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
+	if connection.UserId == connection.ConnectedUserId {
+		Log.Warn("Cant create connection, user with id: " + connection.UserId + " can not connect to themselves.")
+		return nil, ErrSelfConnection
+	}
+
 	isBlocked, _ := service.blockService.IsBlockedAny(ctx, connection.UserId, connection.ConnectedUserId)
 
 	if isBlocked {
 		Log.Warn("Cant create connection, user with id: " + connection.UserId + " is blocked.")
-		return nil, errors.New("user is blocked")
+		return nil, ErrBlocked
+	}
+
+	existing, err := service.store.GetConnectionByUsersId(ctx, connection.UserId, connection.ConnectedUserId)
+	if err != nil && !errors.Is(err, model.ErrConnectionNotFound) {
+		Log.Error("Error while creating connection. Error: " + err.Error())
+		return nil, err
+	}
+	if err == nil && (existing.IsConnected || existing.PendingConnection) {
+		Log.Warn("Cant create connection, user with id: " + connection.UserId + " is already connected to user with id: " + connection.ConnectedUserId)
+		return nil, ErrAlreadyConnected
+	}
+
+	connection.Message = strings.TrimSpace(connection.Message)
+	if utf8.RuneCountInString(connection.Message) > service.config.MaxMessageLength {
+		Log.Warn("Cant create connection, message of user with id: " + connection.UserId + " is too long.")
+		return nil, ErrMessageTooLong
 	}
 
 	isPrivate, err := service.userClient.IsUserPrivateRequest(ctx, &userService.UserIdRequest{UserId: connection.ConnectedUserId})
@@ -56,9 +99,34 @@ func (service *ConnectionService) CreateConnection(ctx context.Context, connecti
 	} else {
 		connection.IsConnected = true
 		connection.PendingConnection = false
+		connection.Message = ""
 	}
 
-	return service.store.CreateConnection(ctx, connection)
+	created, err := service.store.CreateConnection(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+	service.suggestionCache.Invalidate(connection.UserId)
+	metrics.ConnectionEvent(metrics.ConnectionCreated)
+	// requests to private users are attributed once they are approved
+	if created.IsConnected {
+		service.acceptSuggestion(ctx, created, time.Now().UTC())
+	}
+	return created, nil
+}
+
+// acceptSuggestion attributes a connection that just became approved to the latest suggestion of the connected
+// user shown within the attribution window before the user acted at actedAt. Failures are only logged.
+func (service *ConnectionService) acceptSuggestion(ctx context.Context, connection *model.Connection, actedAt time.Time) {
+	now := time.Now().UTC()
+	impression, err := service.impressionStore.AcceptImpression(ctx, connection.UserId, connection.ConnectedUserId, actedAt.Add(-service.config.AttributionWindow), now)
+	if err != nil {
+		Log.Warn("Cant attribute connection of user with id: " + connection.UserId + " to a suggestion. Error: " + err.Error())
+		return
+	}
+	if impression != nil {
+		recordSuggestionEvent(SuggestionAccepted, impression)
+	}
 }
 
 func (service *ConnectionService) ApproveConnection(ctx context.Context, userId string, connectedUserId string) (*model.Connection, error) {
@@ -66,30 +134,47 @@ func (service *ConnectionService) ApproveConnection(ctx context.Context, userId
 
 	span := tracer.StartSpanFromContext(ctx, "ApproveConnection")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, connectedUserId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
+	return service.approveConnection(ctx, userId, connectedUserId)
+}
+
+// approveConnection approves a pending request without checking the caller, which ApproveAllConnection did once.
+func (service *ConnectionService) approveConnection(ctx context.Context, userId string, connectedUserId string) (*model.Connection, error) {
 	isBlocked, _ := service.blockService.IsBlockedAny(ctx, userId, connectedUserId)
 
 	if isBlocked {
 		Log.Warn("Cant approve connection, user with id: " + connectedUserId + " is blocked.")
-		return nil, errors.New("user is blocked")
+		return nil, ErrBlocked
 	}
 
 	connection, err := service.store.GetConnectionByUsersId(ctx, userId, connectedUserId)
 	if err != nil {
 		Log.Error("Error while approving connection. Error: " + err.Error())
-		return nil, err
+		return nil, domainError(err)
 	}
 	if connection.PendingConnection {
 		connection.IsConnected = true
 		connection.PendingConnection = false
+		connection.ApprovedAt = time.Now().UTC()
+		connection.Message = ""
 	} else {
-		return nil, errors.New("not pending connection")
+		return nil, ErrNotPending
 	}
 	conn, err := service.store.UpdateConnection(ctx, connection)
 	if err != nil {
-		return nil, err
+		return nil, domainError(err)
 	}
+	service.suggestionCache.Invalidate(userId)
+	metrics.ConnectionEvent(metrics.ConnectionApproved)
+	requestedAt := conn.RequestedAt
+	if requestedAt.IsZero() {
+		requestedAt = conn.ApprovedAt
+	}
+	service.acceptSuggestion(ctx, conn, requestedAt)
 	return conn, nil
 }
 
@@ -98,22 +183,58 @@ func (service *ConnectionService) RejectConnection(ctx context.Context, userId s
 
 	span := tracer.StartSpanFromContext(ctx, "RejectConnection")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, connectedUserId); err != nil {
+		return err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	isBlocked, _ := service.blockService.IsBlockedAny(ctx, userId, connectedUserId)
 
 	if isBlocked {
-		return errors.New("user is blocked")
+		return ErrBlocked
 	}
 
 	connection, err := service.store.GetConnectionByUsersId(ctx, userId, connectedUserId)
 	if err != nil {
-		return err
+		return domainError(err)
 	}
 	if !connection.PendingConnection {
-		return errors.New("not pending connection")
+		return ErrNotPending
+	}
+	err = service.store.DeleteConnection(ctx, userId, connectedUserId)
+	if err != nil {
+		return domainError(err)
+	}
+	service.suggestionCache.Invalidate(userId)
+	metrics.ConnectionEvent(metrics.ConnectionRejected)
+	recordConnectionEvent(ConnectionRequestRejected, userId, connectedUserId)
+	return nil
+}
+
+// WithdrawConnectionRequest lets the requester take back a request that has not been answered yet.
+func (service *ConnectionService) WithdrawConnectionRequest(ctx context.Context, userId string, connectedUserId string) error {
+	Log.Info("Withdrawing connection request by user with id: " + userId + " , to user with id: " + connectedUserId)
+
+	span := tracer.StartSpanFromContext(ctx, "WithdrawConnectionRequest")
+	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	deleted, err := service.store.DeletePendingConnection(ctx, userId, connectedUserId)
+	if err != nil {
+		Log.Error("Error while withdrawing connection request. Error: " + err.Error())
+		return err
 	}
-	return service.store.DeleteConnection(ctx, userId, connectedUserId)
+	if !deleted {
+		Log.Warn("Cant withdraw connection request, no pending request from user with id: " + userId + " to user with id: " + connectedUserId)
+		return ErrNotPending
+	}
+	service.suggestionCache.Invalidate(userId)
+	metrics.ConnectionEvent(metrics.ConnectionWithdrawn)
+	recordConnectionEvent(ConnectionRequestWithdrawn, userId, connectedUserId)
+	return nil
 }
 
 func (service *ConnectionService) DeleteConnection(ctx context.Context, userId string, connectedUserId string) error {
@@ -121,68 +242,97 @@ func (service *ConnectionService) DeleteConnection(ctx context.Context, userId s
 
 	span := tracer.StartSpanFromContext(ctx, "DeleteConnection")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId, connectedUserId); err != nil {
+		return err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	isBlocked, _ := service.blockService.IsBlockedAny(ctx, userId, connectedUserId)
 
 	if isBlocked {
-		return errors.New("user is blocked")
+		return ErrBlocked
 	}
 
-	return service.store.DeleteConnection(ctx, userId, connectedUserId)
+	err := service.store.DeleteConnection(ctx, userId, connectedUserId)
+	if err != nil {
+		return domainError(err)
+	}
+	service.suggestionCache.Invalidate(userId)
+	metrics.ConnectionEvent(metrics.ConnectionDeleted)
+	return nil
 }
 
 // GetAllConnectionsByUserId isConnected = true || false
-func (service *ConnectionService) GetAllConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
+func (service *ConnectionService) GetAllConnectionsByUserId(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
 	Log.Info("Get all connections of user with id: " + userId)
 
 	span := tracer.StartSpanFromContext(ctx, "GetAllConnectionsByUserId")
 	defer span.Finish()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetAllConnectionsByUserId(ctx, userId)
+	return service.store.GetAllConnectionsPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
 // GetFollowings isConnected = true
-func (service *ConnectionService) GetFollowings(ctx context.Context, userId string) ([]*model.Connection, error) {
+func (service *ConnectionService) GetFollowings(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
 	Log.Info("Get followings of user with id: " + userId)
 
 	span := tracer.StartSpanFromContext(ctx, "GetConnectionsByUserId")
 	defer span.Finish()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetFollowings(ctx, userId)
+	return service.store.GetFollowingsPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
 // GetFollowers isConnected = true
-func (service *ConnectionService) GetFollowers(ctx context.Context, connectedUserId string) ([]*model.Connection, error) {
+func (service *ConnectionService) GetFollowers(ctx context.Context, connectedUserId string, page model.PageRequest) (*model.ConnectionPage, error) {
 	Log.Info("Get followers of user with id: " + connectedUserId)
 
 	span := tracer.StartSpanFromContext(ctx, "GetConnectionsByConnectedUserid")
 	defer span.Finish()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetFollowers(ctx, connectedUserId)
+	return service.store.GetFollowersPage(ctx, connectedUserId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
-func (service *ConnectionService) GetAllRequestConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
+func (service *ConnectionService) GetAllRequestConnectionsByUserId(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
 	Log.Info("Get all request connections of user with id: " + userId)
 
 	span := tracer.StartSpanFromContext(ctx, "GetAllRequestConnectionsByUserId")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetAllRequestConnectionsByUserId(ctx, userId)
+	return service.store.GetAllRequestConnectionsPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
-func (service *ConnectionService) GetAllPendingConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
+func (service *ConnectionService) GetAllPendingConnectionsByUserId(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
 	Log.Info("Get all pending connections of user with id: " + userId)
 
 	span := tracer.StartSpanFromContext(ctx, "GetAllPendingConnectionsByUserId")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	return service.store.GetAllPendingConnectionsPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
+}
+
+// GetAllExpiredConnectionsByUserId returns requests sent by the user that expired before they were answered.
+func (service *ConnectionService) GetAllExpiredConnectionsByUserId(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	Log.Info("Get all expired connections of user with id: " + userId)
+
+	span := tracer.StartSpanFromContext(ctx, "GetAllExpiredConnectionsByUserId")
+	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetAllPendingConnectionsByUserId(ctx, userId)
+	return service.store.GetAllExpiredConnectionsPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
 func (service *ConnectionService) ApproveAllConnection(ctx context.Context, userId string) error {
@@ -190,15 +340,18 @@ func (service *ConnectionService) ApproveAllConnection(ctx context.Context, user
 
 	span := tracer.StartSpanFromContext(ctx, "ApproveAllConnection")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	pendingConnections, err := service.GetAllRequestConnectionsByUserId(ctx, userId)
+	pendingConnections, err := service.store.GetAllRequestConnectionsByUserId(ctx, userId)
 	if err != nil {
 		return err
 	}
 
 	for _, connection := range pendingConnections {
-		_, err := service.ApproveConnection(ctx, connection.UserId, connection.ConnectedUserId)
+		_, err := service.approveConnection(ctx, connection.UserId, connection.ConnectedUserId)
 		if err != nil {
 			return err
 		}
@@ -212,24 +365,27 @@ func (service *ConnectionService) ChangeMessageNotification(ctx context.Context,
 
 	span := tracer.StartSpanFromContextMetadata(ctx, "ChangeMessageNotification")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	isBlocked, _ := service.blockService.IsBlockedAny(ctx, userId, connectedUserId)
 
 	if isBlocked {
-		return nil, errors.New("user is blocked")
+		return nil, ErrBlocked
 	}
 
 	connection, err := service.store.GetConnectionByUsersId(ctx, userId, connectedUserId)
 	if err != nil {
-		return nil, err
+		return nil, domainError(err)
 	}
 
 	connection.IsMessageNotificationEnabled = !connection.IsMessageNotificationEnabled
 
 	conn, err := service.store.UpdateConnection(ctx, connection)
 	if err != nil {
-		return nil, err
+		return nil, domainError(err)
 	}
 	return conn, nil
 }
@@ -239,24 +395,27 @@ func (service *ConnectionService) ChangePostNotification(ctx context.Context, us
 
 	span := tracer.StartSpanFromContextMetadata(ctx, "ChangePostNotification")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	isBlocked, _ := service.blockService.IsBlockedAny(ctx, userId, connectedUserId)
 
 	if isBlocked {
-		return nil, errors.New("user is blocked")
+		return nil, ErrBlocked
 	}
 
 	connection, err := service.store.GetConnectionByUsersId(ctx, userId, connectedUserId)
 	if err != nil {
-		return nil, err
+		return nil, domainError(err)
 	}
 
 	connection.IsPostNotificationEnabled = !connection.IsPostNotificationEnabled
 
 	conn, err := service.store.UpdateConnection(ctx, connection)
 	if err != nil {
-		return nil, err
+		return nil, domainError(err)
 	}
 	return conn, nil
 }
@@ -266,24 +425,27 @@ func (service *ConnectionService) ChangeCommentNotification(ctx context.Context,
 
 	span := tracer.StartSpanFromContextMetadata(ctx, "ChangeCommentNotification")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	isBlocked, _ := service.blockService.IsBlockedAny(ctx, userId, connectedUserId)
 
 	if isBlocked {
-		return nil, errors.New("user is blocked")
+		return nil, ErrBlocked
 	}
 
 	connection, err := service.store.GetConnectionByUsersId(ctx, userId, connectedUserId)
 	if err != nil {
-		return nil, err
+		return nil, domainError(err)
 	}
 
 	connection.IsCommentNotificationEnabled = !connection.IsCommentNotificationEnabled
 
 	conn, err := service.store.UpdateConnection(ctx, connection)
 	if err != nil {
-		return nil, err
+		return nil, domainError(err)
 	}
 	return conn, nil
 }
@@ -293,50 +455,283 @@ func (service *ConnectionService) GetConnection(ctx context.Context, userId stri
 
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetConnection")
 	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId, connectedUserId); err != nil {
+		return nil, err
+	}
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return service.store.GetConnectionByUsersId(ctx, userId, connectedUserId)
+	connection, err := service.store.GetConnectionByUsersId(ctx, userId, connectedUserId)
+	if err != nil {
+		return nil, domainError(err)
+	}
+	return connection, nil
 }
 
-func (service *ConnectionService) GetAllSuggestionsByUserId(ctx context.Context, userId string) ([]string, error) {
-	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllSuggestionsByUserId")
+// GetMutualConnections returns how many users both users follow and a sample of them, without users
+// blocked by or blocking either of them. The sample size is capped at config.MutualSampleSize.
+func (service *ConnectionService) GetMutualConnections(ctx context.Context, userId string, otherUserId string, sampleSize int) (*model.MutualConnections, error) {
+	Log.Info("Get mutual connections of users with id1: " + userId + ", id2: " + otherUserId)
+
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetMutualConnections")
 	defer span.Finish()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	potentialUsers := StringSet{set: map[string]bool{}}
+	isBlocked, _ := service.blockService.IsBlockedAny(ctx, userId, otherUserId)
+
+	if isBlocked {
+		return nil, ErrBlocked
+	}
 
-	followings, err := service.store.GetFollowings(ctx, userId)
+	excluded, err := service.blockService.getBlockedAny(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	otherExcluded, err := service.blockService.getBlockedAny(ctx, otherUserId)
 	if err != nil {
 		return nil, err
 	}
+	excluded = append(excluded, otherExcluded...)
 
-	// friends of my friends
-	for _, connection := range followings {
-		users, err := service.store.GetFollowingsOfMyFollowings(ctx, connection.ConnectedUserId, userId)
-		if err == nil {
-			for _, user := range users {
-				potentialUsers.Add(user)
-			}
+	if sampleSize <= 0 || sampleSize > service.config.MutualSampleSize {
+		sampleSize = service.config.MutualSampleSize
+	}
+
+	return service.store.GetMutualConnections(ctx, userId, otherUserId, excluded, sampleSize)
+}
+
+// GetDegree returns how many hops of approved connections separate the users, up to config.MaxDegreeDepth.
+func (service *ConnectionService) GetDegree(ctx context.Context, userId string, targetUserId string, withPath bool) (*model.Degree, error) {
+	Log.Info("Get degree between users with id1: " + userId + ", id2: " + targetUserId)
+
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetDegree")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	degrees, err := service.GetDegrees(ctx, userId, []string{targetUserId}, withPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(degrees) == 0 {
+		return &model.Degree{UserId: targetUserId}, nil
+	}
+	return degrees[0], nil
+}
+
+// GetDegrees is the batched GetDegree. Chains never pass through users blocked by or blocking the viewer,
+// and blocked targets are reported as unreachable.
+func (service *ConnectionService) GetDegrees(ctx context.Context, userId string, targetUserIds []string, withPath bool) ([]*model.Degree, error) {
+	Log.Info("Get degrees of user with id: " + userId)
+
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetDegrees")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	if len(targetUserIds) > service.config.MaxPageSize {
+		return nil, ErrTooManyTargets
+	}
+
+	excluded, err := service.blockService.getBlockedAny(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	degrees, err := service.store.GetDegrees(ctx, userId, targetUserIds, excluded, service.config.MaxDegreeDepth, withPath)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked := StringSet{set: map[string]bool{}}
+	for _, id := range excluded {
+		blocked.Add(id)
+	}
+	for _, degree := range degrees {
+		if blocked.set[degree.UserId] {
+			degree.Degree = 0
+			degree.Path = nil
 		}
 	}
+	return degrees, nil
+}
+
+// SuggestionOptions tune GetAllSuggestionsByUserId. The zero value uses the configured strategy.
+type SuggestionOptions struct {
+	Strategy string
+	// ExcludePrivate leaves private users out of the cold-start fill-up, since following them only sends a request.
+	ExcludePrivate bool
+}
+
+// GetAllSuggestionsByUserId returns suggestions ranked by the selected strategy, best first, filled up by
+// ColdStart when the strategy finds too few. Lists for the zero SuggestionOptions are served from the cache
+// while they are younger than config.SuggestionCacheTTL and computed on a miss.
+func (service *ConnectionService) GetAllSuggestionsByUserId(ctx context.Context, userId string, options SuggestionOptions) ([]*model.Suggestion, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllSuggestionsByUserId")
+	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	users, err := service.store.GetRandom(ctx, userId, 15-potentialUsers.length())
-	if err == nil {
-		for _, user := range users {
-			potentialUsers.Add(user)
+	var cached *CachedSuggestions
+	if options == (SuggestionOptions{}) {
+		var generation uint64
+		cached, generation = service.suggestionCache.Get(userId, time.Now().Add(-service.config.SuggestionCacheTTL))
+		if cached == nil {
+			computed, err := service.computeSuggestions(ctx, userId, options)
+			if err != nil {
+				return nil, err
+			}
+			service.suggestionCache.Put(userId, generation, computed)
+			cached = computed
+		}
+	} else {
+		computed, err := service.computeSuggestions(ctx, userId, options)
+		if err != nil {
+			return nil, err
 		}
+		cached = computed
+	}
+
+	service.recordImpressions(ctx, userId, cached.Experiment, cached.Variant, cached.Suggestions)
+	return cached.Suggestions, nil
+}
+
+// RefreshSuggestions recomputes the cached default list of the user.
+func (service *ConnectionService) RefreshSuggestions(ctx context.Context, userId string) error {
+	generation := service.suggestionCache.Generation(userId)
+	computed, err := service.computeSuggestions(ctx, userId, SuggestionOptions{})
+	if err != nil {
+		return err
+	}
+	service.suggestionCache.Put(userId, generation, computed)
+	return nil
+}
+
+func (service *ConnectionService) computeSuggestions(ctx context.Context, userId string, options SuggestionOptions) (*CachedSuggestions, error) {
+	computedAt := time.Now()
+
+	strategyName := options.Strategy
+	experiment := ""
+	if strategyName == "" && service.experiment != nil {
+		experiment = service.experiment.Name
+		strategyName = service.experiment.Variant(userId)
+	}
+	if strategyName == "" {
+		strategyName = service.config.SuggestionStrategy
+	}
+	strategy, found := service.strategies[strategyName]
+	if !found {
+		Log.Warn("Unknown suggestion strategy: " + strategyName)
+		return nil, ErrUnknownStrategy
 	}
 
-	var retVal []string
+	ranker := newSuggestionRanker()
+
+	// strategies already leave out blocked users and users i am connected with
+	suggestions, err := strategy.Suggest(ctx, userId, suggestionsLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, suggestion := range suggestions {
+		ranker.Add(suggestion)
+	}
 
-	for i, _ := range potentialUsers.set {
-		blockedAny, _ := service.blockService.IsBlockedAny(ctx, userId, i)
-		if i != userId && !blockedAny {
-			retVal = append(retVal, i)
+	// cold start leaves out the same users as the strategies
+	if ranker.Len() < suggestionsLimit {
+		fillUp, err := service.coldStart.Suggest(ctx, userId, suggestionsLimit-ranker.Len(), options.ExcludePrivate, ranker.Contains)
+		if err != nil {
+			Log.Warn("Cant fill up suggestions for user with id: " + userId + ". Error: " + err.Error())
 		}
+		for _, suggestion := range fillUp {
+			ranker.Add(suggestion)
+		}
+	}
+
+	return &CachedSuggestions{
+		Suggestions: ranker.Suggestions(),
+		Experiment:  experiment,
+		Variant:     strategyName,
+		ComputedAt:  computedAt,
+	}, nil
+}
+
+// recordImpressions stores the suggestions shown to the user under the experiment variant. Failures are
+// only logged so suggestions are still returned.
+func (service *ConnectionService) recordImpressions(ctx context.Context, userId string, experiment string, variant string, suggestions []*model.Suggestion) {
+	now := time.Now().UTC()
+	var impressions []*model.SuggestionImpression
+	for _, suggestion := range suggestions {
+		impressions = append(impressions, &model.SuggestionImpression{
+			UserId:          userId,
+			SuggestedUserId: suggestion.UserId,
+			Experiment:      experiment,
+			Variant:         variant,
+			LastShownAt:     now,
+		})
+	}
+	if err := service.impressionStore.RecordImpressions(ctx, impressions); err != nil {
+		Log.Warn("Cant record suggestion impressions for user with id: " + userId + ". Error: " + err.Error())
+	}
+}
+
+// DismissSuggestion keeps dismissedUserId out of the user's suggestions for config.DismissalTTL.
+func (service *ConnectionService) DismissSuggestion(ctx context.Context, userId string, dismissedUserId string) error {
+	Log.Info("Dismissing suggestion of user with id: " + dismissedUserId + " for user with id: " + userId)
+
+	span := tracer.StartSpanFromContext(ctx, "DismissSuggestion")
+	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	now := time.Now().UTC()
+	err := service.dismissalStore.DismissSuggestion(ctx, model.Dismissal{
+		UserId:          userId,
+		DismissedUserId: dismissedUserId,
+		DismissedAt:     now,
+		ExpiresAt:       now.Add(service.config.DismissalTTL),
+	})
+	if err != nil {
+		Log.Error("Error while dismissing suggestion. Error: " + err.Error())
+		return err
+	}
+	service.suggestionCache.Invalidate(userId)
+	recordConnectionEvent(SuggestionDismissed, userId, dismissedUserId)
+	return nil
+}
+
+func (service *ConnectionService) UndoDismissSuggestion(ctx context.Context, userId string, dismissedUserId string) error {
+	Log.Info("Undoing dismissal of user with id: " + dismissedUserId + " by user with id: " + userId)
+
+	span := tracer.StartSpanFromContext(ctx, "UndoDismissSuggestion")
+	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return err
+	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	undone, err := service.dismissalStore.UndoDismissal(ctx, userId, dismissedUserId)
+	if err != nil {
+		Log.Error("Error while undoing dismissal. Error: " + err.Error())
+		return err
+	}
+	if !undone {
+		Log.Warn("Cant undo dismissal, user with id: " + userId + " did not dismiss user with id: " + dismissedUserId)
+		return ErrNoDismissal
+	}
+	service.suggestionCache.Invalidate(userId)
+	return nil
+}
+
+func (service *ConnectionService) GetDismissedSuggestions(ctx context.Context, userId string, page model.PageRequest) (*model.DismissalPage, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetDismissedSuggestions")
+	defer span.Finish()
+	if err := authorizeActingUser(ctx, userId); err != nil {
+		return nil, err
 	}
+	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	return retVal, nil
+	return service.dismissalStore.GetDismissalsPage(ctx, userId, newPageRequest(service.config, page.Cursor, page.Size))
 }
 
 type StringSet struct {
@@ -348,7 +743,3 @@ func (set *StringSet) Add(i string) bool {
 	set.set[i] = true
 	return !found //False if it existed already
 }
-
-func (set *StringSet) length() int {
-	return len(set.set)
-}
@@ -0,0 +1,41 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"errors"
+)
+
+// DomainError is a failure the caller can act on. Reason is stable, so clients switch on it instead of
+// on the message.
+type DomainError struct {
+	Reason  string
+	Message string
+}
+
+func (err *DomainError) Error() string {
+	return err.Message
+}
+
+var (
+	ErrBlocked          = &DomainError{Reason: "BLOCKED", Message: "user is blocked"}
+	ErrNotPending       = &DomainError{Reason: "NOT_PENDING", Message: "connection is not pending"}
+	ErrNotFound         = &DomainError{Reason: "NOT_FOUND", Message: "connection not found"}
+	ErrAlreadyConnected = &DomainError{Reason: "ALREADY_CONNECTED", Message: "connection already exists"}
+	ErrSelfConnection   = &DomainError{Reason: "SELF_CONNECTION", Message: "user can not connect to themselves"}
+	ErrMessageTooLong   = &DomainError{Reason: "MESSAGE_TOO_LONG", Message: "connection request message is too long"}
+	ErrTooManyTargets   = &DomainError{Reason: "TOO_MANY_TARGETS", Message: "too many target users"}
+	ErrUnknownStrategy  = &DomainError{Reason: "UNKNOWN_STRATEGY", Message: "unknown suggestion strategy"}
+	ErrNoDismissal      = &DomainError{Reason: "NO_DISMISSAL", Message: "suggestion was not dismissed"}
+	ErrUnauthenticated  = &DomainError{Reason: "UNAUTHENTICATED", Message: "missing or invalid access token"}
+	ErrNotActingUser    = &DomainError{Reason: "NOT_ACTING_USER", Message: "caller can not act on behalf of another user"}
+	ErrNotAdmin         = &DomainError{Reason: "NOT_ADMIN", Message: "caller is not an administrator"}
+	ErrNoBlock          = &DomainError{Reason: "NO_BLOCK", Message: "user is not blocked"}
+)
+
+// domainError turns store errors that have a domain meaning into domain errors.
+func domainError(err error) error {
+	if errors.Is(err, model.ErrConnectionNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,21 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"connection-microservice/startup/config"
+)
+
+// newPageRequest falls back to the default page size and caps the size at the configured maximum. The size is
+// never below 1, since pages are cut at size-1.
+func newPageRequest(c *config.Config, cursor string, size int) model.PageRequest {
+	if size <= 0 {
+		size = c.DefaultPageSize
+	}
+	if size > c.MaxPageSize {
+		size = c.MaxPageSize
+	}
+	if size < 1 {
+		size = 1
+	}
+	return model.PageRequest{Cursor: cursor, Size: size}
+}
@@ -0,0 +1,28 @@
+package application
+
+import (
+	"connection-microservice/startup/config"
+	"testing"
+)
+
+func TestNewPageRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.Config
+		size     int
+		wantSize int
+	}{
+		{name: "requested size", config: config.Config{DefaultPageSize: 20, MaxPageSize: 100}, size: 5, wantSize: 5},
+		{name: "default size", config: config.Config{DefaultPageSize: 20, MaxPageSize: 100}, size: 0, wantSize: 20},
+		{name: "capped size", config: config.Config{DefaultPageSize: 20, MaxPageSize: 100}, size: 500, wantSize: 100},
+		{name: "zero max page size", config: config.Config{DefaultPageSize: 20}, size: 5, wantSize: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := newPageRequest(&test.config, "", test.size).Size; got != test.wantSize {
+				t.Fatalf("got size %d, want %d", got, test.wantSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package application
+
+import (
+	"connection-microservice/metrics"
+	"connection-microservice/model"
+	"connection-microservice/startup/config"
+	"context"
+	"time"
+)
+
+// stalePendingAge is how old a pending connection request has to be to count as stale.
+const stalePendingAge = 24 * time.Hour
+
+// PendingConnectionsMonitor periodically counts pending connection requests older than a day for the
+// stale pending connections gauge.
+type PendingConnectionsMonitor struct {
+	*PeriodicWorker
+	store  model.ConnectionStore
+	config *config.Config
+}
+
+func NewPendingConnectionsMonitor(store model.ConnectionStore, c *config.Config) *PendingConnectionsMonitor {
+	monitor := &PendingConnectionsMonitor{
+		store:  store,
+		config: c,
+	}
+	monitor.PeriodicWorker = NewPeriodicWorker(c.MetricsInterval, monitor.CountStalePendingConnections)
+	return monitor
+}
+
+func (monitor *PendingConnectionsMonitor) CountStalePendingConnections(ctx context.Context) {
+	stale, err := monitor.store.CountPendingConnections(ctx, time.Now().Add(-stalePendingAge).UTC())
+	if err != nil {
+		Log.Error("Error while counting stale pending connections. Error: " + err.Error())
+		return
+	}
+	metrics.SetStalePendingConnections(stale)
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"context"
+	"time"
+)
+
+// PeriodicWorker calls run once when started and then every interval on its own goroutine. Stop cancels the
+// context passed to run and waits for a run in progress to finish, so run should return early once it is done.
+type PeriodicWorker struct {
+	interval time.Duration
+	run      func(ctx context.Context)
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewPeriodicWorker panics unless interval is positive, which config.Validate checks for every interval.
+func NewPeriodicWorker(interval time.Duration, run func(ctx context.Context)) *PeriodicWorker {
+	if interval <= 0 {
+		panic("periodic worker interval must be positive")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &PeriodicWorker{
+		interval: interval,
+		run:      run,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+func (worker *PeriodicWorker) Start() {
+	go func() {
+		defer close(worker.done)
+
+		ticker := time.NewTicker(worker.interval)
+		defer ticker.Stop()
+
+		worker.run(worker.ctx)
+		for {
+			select {
+			case <-ticker.C:
+				worker.run(worker.ctx)
+			case <-worker.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (worker *PeriodicWorker) Stop() {
+	worker.cancel()
+	<-worker.done
+}
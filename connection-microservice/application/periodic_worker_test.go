@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPeriodicWorkerRunsUntilStopped(t *testing.T) {
+	var runs int32
+	worker := NewPeriodicWorker(10*time.Millisecond, func(ctx context.Context) {
+		atomic.AddInt32(&runs, 1)
+	})
+
+	worker.Start()
+	time.Sleep(55 * time.Millisecond)
+	worker.Stop()
+
+	stopped := atomic.LoadInt32(&runs)
+	if stopped < 2 {
+		t.Fatalf("ran %d times, want a run at start and one per interval", stopped)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if after := atomic.LoadInt32(&runs); after != stopped {
+		t.Fatalf("ran %d times after Stop returned", after-stopped)
+	}
+}
+
+func TestPeriodicWorkerStopCancelsRun(t *testing.T) {
+	started := make(chan struct{})
+	finished := false
+	worker := NewPeriodicWorker(time.Hour, func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		finished = true
+	})
+
+	worker.Start()
+	<-started
+	worker.Stop()
+
+	if !finished {
+		t.Fatal("Stop must wait for the run in progress")
+	}
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"sync"
+	"time"
+)
+
+// SuggestionCache keeps the latest default suggestion list of every user who asked for suggestions recently.
+// Invalidate bumps the user's generation, so a list computed before an invalidation is never stored after it.
+type SuggestionCache struct {
+	lock    sync.Mutex
+	entries map[string]*suggestionCacheEntry
+}
+
+type suggestionCacheEntry struct {
+	generation      uint64
+	suggestions     []*model.Suggestion
+	experiment      string
+	variant         string
+	computedAt      time.Time
+	lastRequestedAt time.Time
+}
+
+// CachedSuggestions is a suggestion list together with the experiment variant it was computed for.
+type CachedSuggestions struct {
+	Suggestions []*model.Suggestion
+	Experiment  string
+	Variant     string
+	ComputedAt  time.Time
+}
+
+func NewSuggestionCache() *SuggestionCache {
+	return &SuggestionCache{entries: map[string]*suggestionCacheEntry{}}
+}
+
+// Get marks the user as active and returns the cached list if it was computed after computedAfter.
+// The returned generation has to be passed to Put.
+func (cache *SuggestionCache) Get(userId string, computedAfter time.Time) (*CachedSuggestions, uint64) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	entry := cache.entry(userId)
+	entry.lastRequestedAt = time.Now()
+	if entry.suggestions == nil || entry.computedAt.Before(computedAfter) {
+		return nil, entry.generation
+	}
+	return &CachedSuggestions{
+		Suggestions: entry.suggestions,
+		Experiment:  entry.experiment,
+		Variant:     entry.variant,
+		ComputedAt:  entry.computedAt,
+	}, entry.generation
+}
+
+// Generation returns the user's generation without marking the user as active.
+func (cache *SuggestionCache) Generation(userId string) uint64 {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	return cache.entry(userId).generation
+}
+
+// Put stores a list computed at generation. It reports false and drops the list if the user was invalidated since.
+func (cache *SuggestionCache) Put(userId string, generation uint64, suggestions *CachedSuggestions) bool {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	entry := cache.entry(userId)
+	if entry.generation != generation {
+		return false
+	}
+	entry.suggestions = suggestions.Suggestions
+	if entry.suggestions == nil {
+		entry.suggestions = []*model.Suggestion{}
+	}
+	entry.experiment = suggestions.Experiment
+	entry.variant = suggestions.Variant
+	entry.computedAt = suggestions.ComputedAt
+	return true
+}
+
+// Invalidate drops the cached lists of the users.
+func (cache *SuggestionCache) Invalidate(userIds ...string) {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	for _, userId := range userIds {
+		if entry, found := cache.entries[userId]; found {
+			entry.generation++
+			entry.suggestions = nil
+		}
+	}
+}
+
+// Stale returns users who asked for suggestions after activeAfter and whose list was computed before
+// computedBefore or dropped. Users inactive since activeAfter are evicted.
+func (cache *SuggestionCache) Stale(activeAfter time.Time, computedBefore time.Time) []string {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+
+	var userIds []string
+	for userId, entry := range cache.entries {
+		if entry.lastRequestedAt.Before(activeAfter) {
+			delete(cache.entries, userId)
+			continue
+		}
+		if entry.suggestions == nil || entry.computedAt.Before(computedBefore) {
+			userIds = append(userIds, userId)
+		}
+	}
+	return userIds
+}
+
+// entry returns the user's entry, creating it. Callers must hold the lock.
+func (cache *SuggestionCache) entry(userId string) *suggestionCacheEntry {
+	entry, found := cache.entries[userId]
+	if !found {
+		entry = &suggestionCacheEntry{}
+		cache.entries[userId] = entry
+	}
+	return entry
+}
@@ -0,0 +1,26 @@
+package application
+
+import (
+	"hash/fnv"
+)
+
+// SuggestionExperiment splits users between suggestion strategies. A user always lands in the same
+// variant for the same experiment name.
+type SuggestionExperiment struct {
+	Name     string
+	Variants []string
+}
+
+// NewSuggestionExperiment returns nil when there is no experiment to run.
+func NewSuggestionExperiment(name string, variants []string) *SuggestionExperiment {
+	if name == "" || len(variants) == 0 {
+		return nil
+	}
+	return &SuggestionExperiment{Name: name, Variants: variants}
+}
+
+func (experiment *SuggestionExperiment) Variant(userId string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(experiment.Name + ":" + userId))
+	return experiment.Variants[hash.Sum32()%uint32(len(experiment.Variants))]
+}
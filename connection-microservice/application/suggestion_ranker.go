@@ -0,0 +1,62 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"fmt"
+	"sort"
+)
+
+// suggestionRanker merges suggestions that are already scored, keeping the first one of every user, and ranks
+// them by score.
+type suggestionRanker struct {
+	suggestions map[string]*model.Suggestion
+	candidates  []string
+}
+
+func newSuggestionRanker() *suggestionRanker {
+	return &suggestionRanker{suggestions: map[string]*model.Suggestion{}}
+}
+
+// Add records a suggestion unless the user was already suggested.
+func (ranker *suggestionRanker) Add(suggestion *model.Suggestion) {
+	if _, found := ranker.suggestions[suggestion.UserId]; found {
+		return
+	}
+	ranker.candidates = append(ranker.candidates, suggestion.UserId)
+	ranker.suggestions[suggestion.UserId] = suggestion
+}
+
+func (ranker *suggestionRanker) Contains(userId string) bool {
+	_, found := ranker.suggestions[userId]
+	return found
+}
+
+func (ranker *suggestionRanker) Len() int {
+	return len(ranker.candidates)
+}
+
+// Suggestions returns the candidates best first and by user id among equal scores.
+func (ranker *suggestionRanker) Suggestions() []*model.Suggestion {
+	var suggestions []*model.Suggestion
+	for _, userId := range ranker.candidates {
+		suggestions = append(suggestions, ranker.suggestions[userId])
+	}
+	sortSuggestions(suggestions)
+	return suggestions
+}
+
+func sortSuggestions(suggestions []*model.Suggestion) {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].UserId < suggestions[j].UserId
+	})
+}
+
+func followedByExplanation(count int) string {
+	if count == 1 {
+		return "Followed by 1 person you follow"
+	}
+	return fmt.Sprintf("Followed by %d people you follow", count)
+}
@@ -0,0 +1,51 @@
+package application
+
+import (
+	"connection-microservice/startup/config"
+	"context"
+	"fmt"
+	"time"
+)
+
+// SuggestionRefreshWorker periodically recomputes cached suggestion lists of users active within
+// config.ActiveUserWindow before they get older than config.SuggestionCacheTTL.
+type SuggestionRefreshWorker struct {
+	*PeriodicWorker
+	service *ConnectionService
+	cache   *SuggestionCache
+	config  *config.Config
+}
+
+func NewSuggestionRefreshWorker(service *ConnectionService, cache *SuggestionCache, c *config.Config) *SuggestionRefreshWorker {
+	worker := &SuggestionRefreshWorker{
+		service: service,
+		cache:   cache,
+		config:  c,
+	}
+	worker.PeriodicWorker = NewPeriodicWorker(c.SuggestionRefresh, worker.RefreshSuggestions)
+	return worker
+}
+
+// RefreshSuggestions recomputes lists that would expire before the next run.
+func (worker *SuggestionRefreshWorker) RefreshSuggestions(ctx context.Context) {
+	start := time.Now()
+	stale := worker.cache.Stale(start.Add(-worker.config.ActiveUserWindow), start.Add(worker.config.SuggestionRefresh-worker.config.SuggestionCacheTTL))
+
+	refreshed := 0
+	for _, userId := range stale {
+		select {
+		case <-ctx.Done():
+			Log.Info(fmt.Sprintf("Stopped refreshing suggestions after %d of %d users", refreshed, len(stale)))
+			return
+		default:
+		}
+
+		if err := worker.service.RefreshSuggestions(ctx, userId); err != nil {
+			Log.Error("Error while refreshing suggestions for user with id: " + userId + ". Error: " + err.Error())
+			continue
+		}
+		refreshed++
+	}
+
+	Log.Info(fmt.Sprintf("Refreshed suggestions of %d of %d users in %s", refreshed, len(stale), time.Since(start)))
+}
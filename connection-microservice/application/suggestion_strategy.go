@@ -0,0 +1,168 @@
+package application
+
+import (
+	"connection-microservice/model"
+	"context"
+	"fmt"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
+)
+
+const (
+	CommonNeighboursStrategy = "common_neighbours"
+	JaccardStrategy          = "jaccard"
+	AdamicAdarStrategy       = "adamic_adar"
+	MostFollowedStrategy     = "most_followed"
+	PopularityStrategy       = "popularity"
+)
+
+// SuggestionStrategy ranks users the given user may want to follow.
+type SuggestionStrategy interface {
+	Name() string
+	// Suggest returns up to limit scored suggestions, best first.
+	Suggest(ctx context.Context, userId string, limit int) ([]*model.Suggestion, error)
+}
+
+// NewSuggestionStrategies returns every strategy by name. Strategies over the 2-hop neighbourhood score
+// the poolSize candidates followed by most of the user's followings.
+func NewSuggestionStrategies(store model.ConnectionStore, poolSize int) map[string]SuggestionStrategy {
+	strategies := []SuggestionStrategy{
+		&neighbourhoodStrategy{name: CommonNeighboursStrategy, store: store, poolSize: poolSize, score: commonNeighboursScore},
+		&neighbourhoodStrategy{name: JaccardStrategy, store: store, poolSize: poolSize, score: jaccardScore, withFollowingCount: true},
+		&neighbourhoodStrategy{name: AdamicAdarStrategy, store: store, poolSize: poolSize, score: adamicAdarScore},
+		&neighbourhoodStrategy{name: MostFollowedStrategy, store: store, poolSize: poolSize, score: mostFollowedScore},
+		&popularityStrategy{store: store},
+	}
+
+	byName := map[string]SuggestionStrategy{}
+	for _, strategy := range strategies {
+		byName[strategy.Name()] = strategy
+	}
+	return byName
+}
+
+// ValidateSuggestionStrategies checks that the default strategy and every experiment variant name a strategy
+// from NewSuggestionStrategies, so a typo fails at startup instead of on every suggestion request.
+func ValidateSuggestionStrategies(strategy string, variants []string) error {
+	strategies := NewSuggestionStrategies(nil, 0)
+	if _, found := strategies[strategy]; !found {
+		return fmt.Errorf("SUGGESTION_STRATEGY %q is not a suggestion strategy", strategy)
+	}
+	for _, variant := range variants {
+		if _, found := strategies[variant]; !found {
+			return fmt.Errorf("SUGGESTION_VARIANTS entry %q is not a suggestion strategy", variant)
+		}
+	}
+	return nil
+}
+
+// neighbourhoodStrategy scores users followed by the user's followings.
+type neighbourhoodStrategy struct {
+	name     string
+	store    model.ConnectionStore
+	poolSize int
+	// score rates a candidate of a user following followingCount users. followingCount is only counted
+	// when withFollowingCount is set.
+	score              func(candidate *model.SuggestionCandidate, followingCount int) float64
+	withFollowingCount bool
+}
+
+func (strategy *neighbourhoodStrategy) Name() string {
+	return strategy.name
+}
+
+func (strategy *neighbourhoodStrategy) Suggest(ctx context.Context, userId string, limit int) ([]*model.Suggestion, error) {
+	span := tracer.StartSpanFromContext(ctx, "Suggest")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	candidates, err := strategy.store.GetSuggestionCandidates(ctx, userId, strategy.poolSize)
+	if err != nil {
+		return nil, err
+	}
+
+	followingCount := 0
+	if strategy.withFollowingCount {
+		followings, err := strategy.store.GetFollowings(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		followingCount = len(followings)
+	}
+
+	var suggestions []*model.Suggestion
+	for _, candidate := range candidates {
+		suggestion := &model.Suggestion{
+			UserId:      candidate.UserId,
+			Score:       strategy.score(candidate, followingCount),
+			Reason:      model.SuggestionReasonFollowedByFollowings,
+			MutualCount: candidate.MutualCount,
+			Explanation: followedByExplanation(candidate.MutualCount),
+			Strategy:    strategy.name,
+		}
+		if strategy.name == MostFollowedStrategy {
+			suggestion.Reason = model.SuggestionReasonPopularInNetwork
+			suggestion.Explanation = "Popular in your network"
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sortSuggestions(suggestions)
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+func commonNeighboursScore(candidate *model.SuggestionCandidate, _ int) float64 {
+	return float64(candidate.MutualCount)
+}
+
+// jaccardScore divides the followings who follow the candidate by everyone the user follows or who
+// follows the candidate.
+func jaccardScore(candidate *model.SuggestionCandidate, followingCount int) float64 {
+	union := followingCount + candidate.FollowerCount - candidate.MutualCount
+	if union <= 0 {
+		return 0
+	}
+	return float64(candidate.MutualCount) / float64(union)
+}
+
+func adamicAdarScore(candidate *model.SuggestionCandidate, _ int) float64 {
+	return candidate.AdamicAdar
+}
+
+func mostFollowedScore(candidate *model.SuggestionCandidate, _ int) float64 {
+	return float64(candidate.FollowerCount)
+}
+
+// popularityStrategy suggests the most followed users regardless of the user's network.
+type popularityStrategy struct {
+	store model.ConnectionStore
+}
+
+func (strategy *popularityStrategy) Name() string {
+	return PopularityStrategy
+}
+
+func (strategy *popularityStrategy) Suggest(ctx context.Context, userId string, limit int) ([]*model.Suggestion, error) {
+	span := tracer.StartSpanFromContext(ctx, "Suggest")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	users, err := strategy.store.GetPopularUsers(ctx, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var suggestions []*model.Suggestion
+	for _, user := range users {
+		suggestions = append(suggestions, &model.Suggestion{
+			UserId:      user.UserId,
+			Score:       float64(user.FollowerCount),
+			Reason:      model.SuggestionReasonPopular,
+			Explanation: fmt.Sprintf("Followed by %d people", user.FollowerCount),
+			Strategy:    PopularityStrategy,
+		})
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,28 @@
+package application
+
+import (
+	"testing"
+)
+
+func TestValidateSuggestionStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		variants []string
+		wantErr  bool
+	}{
+		{name: "no experiment", strategy: CommonNeighboursStrategy},
+		{name: "known variants", strategy: CommonNeighboursStrategy, variants: []string{JaccardStrategy, PopularityStrategy}},
+		{name: "unknown strategy", strategy: "common-neighbours", wantErr: true},
+		{name: "unknown variant", strategy: CommonNeighboursStrategy, variants: []string{JaccardStrategy, "adamic-adar"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateSuggestionStrategies(test.strategy, test.variants)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"connection-microservice/application"
+	"connection-microservice/model"
+	"context"
+	connectionService "github.com/XWS-BSEP-TIM1-2022/dislinkt/util/proto/connection"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
+)
+
+type AdminHandler struct {
+	connectionService.UnimplementedConnectionAdminServiceServer
+	service *application.AdminService
+}
+
+func NewAdminHandler(service *application.AdminService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+func (handler *AdminHandler) ForceRemoveConnection(ctx context.Context, in *connectionService.Connection) (*connectionService.EmptyRequest, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "ForceRemoveConnection")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	err := handler.service.ForceRemoveConnection(ctx, in.UserId, in.ConnectedUserId)
+	if err != nil {
+		return nil, err
+	}
+	return &connectionService.EmptyRequest{}, nil
+}
+
+func (handler *AdminHandler) ForceRemoveBlock(ctx context.Context, in *connectionService.Block) (*connectionService.EmptyRequest, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "ForceRemoveBlock")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	err := handler.service.ForceRemoveBlock(ctx, in.UserId, in.BlockUserId)
+	if err != nil {
+		return nil, err
+	}
+	return &connectionService.EmptyRequest{}, nil
+}
+
+func (handler *AdminHandler) GetAllBlocks(ctx context.Context, in *connectionService.AllBlocksRequest) (*connectionService.AllBlocksResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllBlocks")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	page, err := handler.service.GetAllBlocks(ctx, model.PageRequest{Cursor: in.Cursor, Size: int(in.PageSize)})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &connectionService.AllBlocksResponse{
+		Blocks:     []*connectionService.Block{},
+		NextCursor: page.NextCursor,
+	}
+	for _, block := range page.Blocks {
+		response.Blocks = append(response.Blocks, mapBlock(block))
+	}
+	return response, nil
+}
+
+func (handler *AdminHandler) GetNeighbourhood(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.NeighbourhoodResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetNeighbourhood")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	neighbourhood, err := handler.service.GetNeighbourhood(ctx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return mapNeighbourhood(neighbourhood), nil
+}
+
+func (handler *AdminHandler) BanUser(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.BanUserResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "BanUser")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	removed, err := handler.service.BanUser(ctx, in.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &connectionService.BanUserResponse{RemovedRelationships: removed}, nil
+}
+
+func (handler *AdminHandler) GetSuggestionExperimentStats(ctx context.Context, in *connectionService.ExperimentStatsRequest) (*connectionService.ExperimentStatsResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetSuggestionExperimentStats")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	stats, err := handler.service.GetSuggestionExperimentStats(ctx, in.Experiment)
+	if err != nil {
+		return nil, err
+	}
+	response := &connectionService.ExperimentStatsResponse{Variants: []*connectionService.VariantStats{}}
+	for _, variant := range stats {
+		response.Variants = append(response.Variants, mapVariantStats(variant))
+	}
+	return response, nil
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"connection-microservice/application"
+	"context"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/token"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"strings"
+)
+
+const authorizationHeader = "authorization"
+
+// healthServicePrefix prefixes the methods of the health service, which orchestrators call without a token.
+var healthServicePrefix = "/" + healthpb.Health_ServiceDesc.ServiceName + "/"
+
+type AuthInterceptor struct {
+	jwtManager *token.JwtManager
+}
+
+func NewAuthInterceptor(jwtManager *token.JwtManager) *AuthInterceptor {
+	return &AuthInterceptor{
+		jwtManager: jwtManager,
+	}
+}
+
+// Unary verifies the access token in the authorization header and puts the caller it was issued to in the
+// context, where the services check it against the user a request acts for.
+func (interceptor *AuthInterceptor) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+		return handler(ctx, req)
+	}
+	claims, err := interceptor.verify(ctx)
+	if err != nil {
+		return nil, application.ErrUnauthenticated
+	}
+	ctx = application.ContextWithCaller(ctx, &application.Caller{UserId: claims.UserId, Role: claims.Role})
+	return handler(ctx, req)
+}
+
+func (interceptor *AuthInterceptor) verify(ctx context.Context) (*token.UserClaims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, application.ErrUnauthenticated
+	}
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return nil, application.ErrUnauthenticated
+	}
+	accessToken := strings.TrimPrefix(values[0], "Bearer ")
+	return interceptor.jwtManager.Verify(accessToken)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"connection-microservice/application"
+	"context"
+	"errors"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"testing"
+	"time"
+)
+
+func TestAuthInterceptorUnary(t *testing.T) {
+	jwtManager := token.NewJwtManagerDislinkt(time.Hour)
+	interceptor := NewAuthInterceptor(jwtManager)
+	accessToken, err := jwtManager.GenerateJWT("user1", "username1", "USER")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		md         metadata.MD
+		called     bool
+		wantCaller *application.Caller
+		wantErr    error
+	}{
+		{
+			name:    "missing metadata",
+			method:  "/connection.ConnectionService/GetConnection",
+			wantErr: application.ErrUnauthenticated,
+		},
+		{
+			name:    "missing header",
+			method:  "/connection.ConnectionService/GetConnection",
+			md:      metadata.Pairs("other", "value"),
+			wantErr: application.ErrUnauthenticated,
+		},
+		{
+			name:    "invalid token",
+			method:  "/connection.ConnectionService/GetConnection",
+			md:      metadata.Pairs(authorizationHeader, "Bearer not-a-token"),
+			wantErr: application.ErrUnauthenticated,
+		},
+		{
+			name:       "valid token",
+			method:     "/connection.ConnectionService/GetConnection",
+			md:         metadata.Pairs(authorizationHeader, "Bearer "+accessToken),
+			called:     true,
+			wantCaller: &application.Caller{UserId: "user1", Role: "USER"},
+		},
+		{
+			name:   "health check without token",
+			method: "/grpc.health.v1.Health/Check",
+			called: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, test.md)
+			}
+
+			called := false
+			var caller *application.Caller
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				caller, _ = application.CallerFromContext(ctx)
+				return "ok", nil
+			}
+			_, err := interceptor.Unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: test.method}, handler)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v, want %v", err, test.wantErr)
+			}
+			if called != test.called {
+				t.Fatalf("handler called: %v, want %v", called, test.called)
+			}
+			if test.wantCaller != nil && (caller == nil || *caller != *test.wantCaller) {
+				t.Fatalf("got caller %+v, want %+v", caller, test.wantCaller)
+			}
+		})
+	}
+}
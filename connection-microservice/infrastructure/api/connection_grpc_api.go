@@ -22,9 +22,9 @@ func NewConnectionHandler(service *application.ConnectionService, blockService *
 func (handler *ConnectionHandler) NewUserConnection(ctx context.Context, in *connectionService.UserConnectionRequest) (*connectionService.UserConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "NewUserConnection")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	connection, err := handler.service.CreateConnection(ctx, &model.Connection{UserId: in.Connection.UserId, ConnectedUserId: in.Connection.ConnectedUserId})
+	connection, err := handler.service.CreateConnection(ctx, &model.Connection{UserId: in.Connection.UserId, ConnectedUserId: in.Connection.ConnectedUserId, Message: in.Connection.Message})
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +34,7 @@ func (handler *ConnectionHandler) NewUserConnection(ctx context.Context, in *con
 func (handler *ConnectionHandler) ApproveConnection(ctx context.Context, in *connectionService.UserConnectionRequest) (*connectionService.UserConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "ApproveConnection")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	connection, err := handler.service.ApproveConnection(ctx, in.Connection.UserId, in.Connection.ConnectedUserId)
 	if err != nil {
@@ -47,7 +47,7 @@ func (handler *ConnectionHandler) ApproveConnection(ctx context.Context, in *con
 func (handler *ConnectionHandler) ApproveAllConnection(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.EmptyRequest, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "ApproveAllConnection")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	err := handler.service.ApproveAllConnection(ctx, in.UserId)
 	if err != nil {
@@ -59,7 +59,7 @@ func (handler *ConnectionHandler) ApproveAllConnection(ctx context.Context, in *
 func (handler *ConnectionHandler) RejectConnection(ctx context.Context, in *connectionService.UserConnectionRequest) (*connectionService.UserConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "RejectConnection")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	err := handler.service.RejectConnection(ctx, in.Connection.UserId, in.Connection.ConnectedUserId)
 	if err != nil {
@@ -69,10 +69,23 @@ func (handler *ConnectionHandler) RejectConnection(ctx context.Context, in *conn
 	return &connectionService.UserConnectionResponse{Connection: nil}, nil
 }
 
+func (handler *ConnectionHandler) WithdrawConnectionRequest(ctx context.Context, in *connectionService.UserConnectionRequest) (*connectionService.UserConnectionResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "WithdrawConnectionRequest")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	err := handler.service.WithdrawConnectionRequest(ctx, in.Connection.UserId, in.Connection.ConnectedUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &connectionService.UserConnectionResponse{Connection: nil}, nil
+}
+
 func (handler *ConnectionHandler) DeleteConnection(ctx context.Context, in *connectionService.Connection) (*connectionService.UserConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "DeleteConnection")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	err := handler.service.DeleteConnection(ctx, in.UserId, in.ConnectedUserId)
 	if err != nil {
@@ -85,7 +98,7 @@ func (handler *ConnectionHandler) DeleteConnection(ctx context.Context, in *conn
 func (handler *ConnectionHandler) GetConnection(ctx context.Context, in *connectionService.Connection) (*connectionService.Connection, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllConnections")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	connection, err := handler.service.GetConnection(ctx, in.UserId, in.ConnectedUserId)
 	if err != nil {
@@ -94,12 +107,12 @@ func (handler *ConnectionHandler) GetConnection(ctx context.Context, in *connect
 	return mapConnection(connection), nil
 }
 
-func (handler *ConnectionHandler) GetAllConnections(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.AllConnectionResponse, error) {
+func (handler *ConnectionHandler) GetAllConnections(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.AllConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllConnections")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	connections, err := handler.service.GetAllConnectionsByUserId(ctx, in.UserId)
+	page, err := handler.service.GetAllConnectionsByUserId(ctx, in.UserId, mapPageRequest(in))
 
 	if err != nil {
 		return nil, err
@@ -107,20 +120,21 @@ func (handler *ConnectionHandler) GetAllConnections(ctx context.Context, in *con
 
 	response := &connectionService.AllConnectionResponse{
 		Connections: []*connectionService.Connection{},
+		NextCursor:  page.NextCursor,
 	}
-	for _, conn := range connections {
+	for _, conn := range page.Connections {
 		current := mapConnection(conn)
 		response.Connections = append(response.Connections, current)
 	}
 	return response, nil
 }
 
-func (handler *ConnectionHandler) GetFollowings(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.AllConnectionResponse, error) {
+func (handler *ConnectionHandler) GetFollowings(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.AllConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetFollowings")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	connections, err := handler.service.GetFollowings(ctx, in.UserId)
+	page, err := handler.service.GetFollowings(ctx, in.UserId, mapPageRequest(in))
 
 	if err != nil {
 		return nil, err
@@ -128,20 +142,21 @@ func (handler *ConnectionHandler) GetFollowings(ctx context.Context, in *connect
 
 	response := &connectionService.AllConnectionResponse{
 		Connections: []*connectionService.Connection{},
+		NextCursor:  page.NextCursor,
 	}
-	for _, conn := range connections {
+	for _, conn := range page.Connections {
 		current := mapConnection(conn)
 		response.Connections = append(response.Connections, current)
 	}
 	return response, nil
 }
 
-func (handler *ConnectionHandler) GetFollowers(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.AllConnectionResponse, error) {
+func (handler *ConnectionHandler) GetFollowers(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.AllConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetFollowers")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	connections, err := handler.service.GetFollowers(ctx, in.UserId)
+	page, err := handler.service.GetFollowers(ctx, in.UserId, mapPageRequest(in))
 
 	if err != nil {
 		return nil, err
@@ -149,20 +164,21 @@ func (handler *ConnectionHandler) GetFollowers(ctx context.Context, in *connecti
 
 	response := &connectionService.AllConnectionResponse{
 		Connections: []*connectionService.Connection{},
+		NextCursor:  page.NextCursor,
 	}
-	for _, conn := range connections {
+	for _, conn := range page.Connections {
 		current := mapConnection(conn)
 		response.Connections = append(response.Connections, current)
 	}
 	return response, nil
 }
 
-func (handler *ConnectionHandler) GetAllRequestConnectionsByUserId(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.AllConnectionResponse, error) {
+func (handler *ConnectionHandler) GetAllRequestConnectionsByUserId(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.AllConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllRequestConnectionsByUserId")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	connections, err := handler.service.GetAllRequestConnectionsByUserId(ctx, in.UserId)
+	page, err := handler.service.GetAllRequestConnectionsByUserId(ctx, in.UserId, mapPageRequest(in))
 
 	if err != nil {
 		return nil, err
@@ -170,20 +186,43 @@ func (handler *ConnectionHandler) GetAllRequestConnectionsByUserId(ctx context.C
 
 	response := &connectionService.AllConnectionResponse{
 		Connections: []*connectionService.Connection{},
+		NextCursor:  page.NextCursor,
 	}
-	for _, conn := range connections {
+	for _, conn := range page.Connections {
 		current := mapConnection(conn)
 		response.Connections = append(response.Connections, current)
 	}
 	return response, nil
 }
 
-func (handler *ConnectionHandler) GetAllPendingConnectionsByUserId(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.AllConnectionResponse, error) {
+func (handler *ConnectionHandler) GetAllPendingConnectionsByUserId(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.AllConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllPendingConnectionsByUserId")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	page, err := handler.service.GetAllPendingConnectionsByUserId(ctx, in.UserId, mapPageRequest(in))
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &connectionService.AllConnectionResponse{
+		Connections: []*connectionService.Connection{},
+		NextCursor:  page.NextCursor,
+	}
+	for _, conn := range page.Connections {
+		current := mapConnection(conn)
+		response.Connections = append(response.Connections, current)
+	}
+	return response, nil
+}
+
+func (handler *ConnectionHandler) GetAllExpiredConnectionsByUserId(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.AllConnectionResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllExpiredConnectionsByUserId")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	connections, err := handler.service.GetAllPendingConnectionsByUserId(ctx, in.UserId)
+	page, err := handler.service.GetAllExpiredConnectionsByUserId(ctx, in.UserId, mapPageRequest(in))
 
 	if err != nil {
 		return nil, err
@@ -191,8 +230,9 @@ func (handler *ConnectionHandler) GetAllPendingConnectionsByUserId(ctx context.C
 
 	response := &connectionService.AllConnectionResponse{
 		Connections: []*connectionService.Connection{},
+		NextCursor:  page.NextCursor,
 	}
-	for _, conn := range connections {
+	for _, conn := range page.Connections {
 		current := mapConnection(conn)
 		response.Connections = append(response.Connections, current)
 	}
@@ -202,9 +242,9 @@ func (handler *ConnectionHandler) GetAllPendingConnectionsByUserId(ctx context.C
 func (handler *ConnectionHandler) BlockUser(ctx context.Context, in *connectionService.BlockUserRequest) (*connectionService.EmptyRequest, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "BlockUser")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	err := handler.blockService.BlockUser(ctx, in.Block.UserId, in.Block.BlockUserId)
+	_, err := handler.blockService.BlockUser(ctx, in.Block.UserId, in.Block.BlockUserId)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +254,7 @@ func (handler *ConnectionHandler) BlockUser(ctx context.Context, in *connectionS
 func (handler *ConnectionHandler) UnblockUser(ctx context.Context, in *connectionService.BlockUserRequest) (*connectionService.EmptyRequest, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "UnblockUser")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	err := handler.blockService.UnblockUser(ctx, in.Block.UserId, in.Block.BlockUserId)
 	if err != nil {
@@ -226,7 +266,7 @@ func (handler *ConnectionHandler) UnblockUser(ctx context.Context, in *connectio
 func (handler *ConnectionHandler) IsBlocked(ctx context.Context, in *connectionService.Block) (*connectionService.IsBlockedResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "IsBlocked")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 	blocked, err := handler.blockService.IsBlocked(ctx, in.UserId, in.BlockUserId)
 	if err != nil {
 		return nil, err
@@ -237,7 +277,7 @@ func (handler *ConnectionHandler) IsBlocked(ctx context.Context, in *connectionS
 func (handler *ConnectionHandler) IsBlockedAny(ctx context.Context, in *connectionService.Block) (*connectionService.IsBlockedResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "IsBlockedAny")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 	blocked, err := handler.blockService.IsBlockedAny(ctx, in.UserId, in.BlockUserId)
 	if err != nil {
 		return &connectionService.IsBlockedResponse{Blocked: false}, err
@@ -245,34 +285,34 @@ func (handler *ConnectionHandler) IsBlockedAny(ctx context.Context, in *connecti
 	return &connectionService.IsBlockedResponse{Blocked: blocked}, nil
 }
 
-func (handler *ConnectionHandler) Blocked(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.BlockedResponse, error) {
+func (handler *ConnectionHandler) Blocked(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.BlockedResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "Blocked")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	blocked, err := handler.blockService.GetBlocked(ctx, in.UserId)
+	page, err := handler.blockService.GetBlocked(ctx, in.UserId, mapPageRequest(in))
 	if err != nil {
 		return nil, err
 	}
-	return &connectionService.BlockedResponse{UsersId: blocked}, nil
+	return &connectionService.BlockedResponse{UsersId: page.UserIds, NextCursor: page.NextCursor}, nil
 }
 
-func (handler *ConnectionHandler) BlockedBy(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.BlockedResponse, error) {
+func (handler *ConnectionHandler) BlockedBy(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.BlockedResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "Blocked")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	blocked, err := handler.blockService.GetBlockedBy(ctx, in.UserId)
+	page, err := handler.blockService.GetBlockedBy(ctx, in.UserId, mapPageRequest(in))
 	if err != nil {
 		return nil, err
 	}
-	return &connectionService.BlockedResponse{UsersId: blocked}, nil
+	return &connectionService.BlockedResponse{UsersId: page.UserIds, NextCursor: page.NextCursor}, nil
 }
 
 func (handler *ConnectionHandler) BlockedAny(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.BlockedResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "Blocked")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	blocked, err := handler.blockService.GetBlockedAny(ctx, in.UserId)
 	if err != nil {
@@ -284,7 +324,7 @@ func (handler *ConnectionHandler) BlockedAny(ctx context.Context, in *connection
 func (handler *ConnectionHandler) ChangeMessageNotification(ctx context.Context, in *connectionService.UserConnectionRequest) (*connectionService.UserConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "ChangeMessageNotification")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	connection, err := handler.service.ChangeMessageNotification(ctx, in.Connection.UserId, in.Connection.ConnectedUserId)
 	if err != nil {
@@ -298,7 +338,7 @@ func (handler *ConnectionHandler) ChangeMessageNotification(ctx context.Context,
 func (handler *ConnectionHandler) ChangePostNotification(ctx context.Context, in *connectionService.UserConnectionRequest) (*connectionService.UserConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "ChangePostNotification")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	connection, err := handler.service.ChangePostNotification(ctx, in.Connection.UserId, in.Connection.ConnectedUserId)
 	if err != nil {
@@ -312,7 +352,7 @@ func (handler *ConnectionHandler) ChangePostNotification(ctx context.Context, in
 func (handler *ConnectionHandler) ChangeCommentNotification(ctx context.Context, in *connectionService.UserConnectionRequest) (*connectionService.UserConnectionResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "ChangeCommentNotification")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
 	connection, err := handler.service.ChangeCommentNotification(ctx, in.Connection.UserId, in.Connection.ConnectedUserId)
 	if err != nil {
@@ -323,12 +363,15 @@ func (handler *ConnectionHandler) ChangeCommentNotification(ctx context.Context,
 
 }
 
-func (handler *ConnectionHandler) GetAllSuggestionsByUserId(ctx context.Context, in *connectionService.UserIdRequest) (*connectionService.SuggestionsResponse, error) {
+func (handler *ConnectionHandler) GetAllSuggestionsByUserId(ctx context.Context, in *connectionService.SuggestionsRequest) (*connectionService.SuggestionsResponse, error) {
 	span := tracer.StartSpanFromContextMetadata(ctx, "GetAllSuggestionsByUserId")
 	defer span.Finish()
-	ctx = tracer.ContextWithSpan(context.Background(), span)
+	ctx = tracer.ContextWithSpan(ctx, span)
 
-	suggestions, err := handler.service.GetAllSuggestionsByUserId(ctx, in.UserId)
+	suggestions, err := handler.service.GetAllSuggestionsByUserId(ctx, in.UserId, application.SuggestionOptions{
+		Strategy:       in.Strategy,
+		ExcludePrivate: in.ExcludePrivate,
+	})
 
 	if err != nil {
 		return nil, err
@@ -336,9 +379,98 @@ func (handler *ConnectionHandler) GetAllSuggestionsByUserId(ctx context.Context,
 
 	response := &connectionService.SuggestionsResponse{
 		SuggestionUserIds: []*connectionService.UserIdRequest{},
+		Suggestions:       []*connectionService.Suggestion{},
+	}
+	for _, suggestion := range suggestions {
+		response.SuggestionUserIds = append(response.SuggestionUserIds, &connectionService.UserIdRequest{UserId: suggestion.UserId})
+		response.Suggestions = append(response.Suggestions, mapSuggestion(suggestion))
+	}
+	return response, nil
+}
+
+func (handler *ConnectionHandler) GetMutualConnections(ctx context.Context, in *connectionService.MutualConnectionsRequest) (*connectionService.MutualConnectionsResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetMutualConnections")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	mutual, err := handler.service.GetMutualConnections(ctx, in.UserId, in.OtherUserId, int(in.SampleSize))
+	if err != nil {
+		return nil, err
+	}
+	return &connectionService.MutualConnectionsResponse{Count: mutual.Count, UserIds: mutual.UserIds}, nil
+}
+
+func (handler *ConnectionHandler) GetDegree(ctx context.Context, in *connectionService.DegreeRequest) (*connectionService.DegreeResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetDegree")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	degree, err := handler.service.GetDegree(ctx, in.UserId, in.TargetUserId, in.IncludePath)
+	if err != nil {
+		return nil, err
+	}
+	return mapDegree(degree), nil
+}
+
+func (handler *ConnectionHandler) GetDegrees(ctx context.Context, in *connectionService.DegreesRequest) (*connectionService.DegreesResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetDegrees")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	degrees, err := handler.service.GetDegrees(ctx, in.UserId, in.TargetUserIds, in.IncludePath)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &connectionService.DegreesResponse{
+		Degrees: []*connectionService.DegreeResponse{},
+	}
+	for _, degree := range degrees {
+		response.Degrees = append(response.Degrees, mapDegree(degree))
+	}
+	return response, nil
+}
+
+func (handler *ConnectionHandler) DismissSuggestion(ctx context.Context, in *connectionService.DismissSuggestionRequest) (*connectionService.EmptyRequest, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "DismissSuggestion")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	err := handler.service.DismissSuggestion(ctx, in.UserId, in.DismissedUserId)
+	if err != nil {
+		return nil, err
+	}
+	return &connectionService.EmptyRequest{}, nil
+}
+
+func (handler *ConnectionHandler) UndoDismissSuggestion(ctx context.Context, in *connectionService.DismissSuggestionRequest) (*connectionService.EmptyRequest, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "UndoDismissSuggestion")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	err := handler.service.UndoDismissSuggestion(ctx, in.UserId, in.DismissedUserId)
+	if err != nil {
+		return nil, err
+	}
+	return &connectionService.EmptyRequest{}, nil
+}
+
+func (handler *ConnectionHandler) GetDismissedSuggestions(ctx context.Context, in *connectionService.UserIdPageRequest) (*connectionService.DismissedSuggestionsResponse, error) {
+	span := tracer.StartSpanFromContextMetadata(ctx, "GetDismissedSuggestions")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(ctx, span)
+
+	page, err := handler.service.GetDismissedSuggestions(ctx, in.UserId, mapPageRequest(in))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &connectionService.DismissedSuggestionsResponse{
+		Dismissals: []*connectionService.Dismissal{},
+		NextCursor: page.NextCursor,
 	}
-	for _, userId := range suggestions {
-		response.SuggestionUserIds = append(response.SuggestionUserIds, &connectionService.UserIdRequest{UserId: userId})
+	for _, dismissal := range page.Dismissals {
+		response.Dismissals = append(response.Dismissals, mapDismissal(dismissal))
 	}
 	return response, nil
 }
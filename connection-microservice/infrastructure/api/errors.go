@@ -0,0 +1,73 @@
+package api
+
+import (
+	"connection-microservice/application"
+	"connection-microservice/model"
+	"context"
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain of every error reason this service reports.
+const errorDomain = "connection-service"
+
+var domainErrorCodes = map[*application.DomainError]codes.Code{
+	application.ErrBlocked:          codes.PermissionDenied,
+	application.ErrNotPending:       codes.FailedPrecondition,
+	application.ErrNotFound:         codes.NotFound,
+	application.ErrAlreadyConnected: codes.AlreadyExists,
+	application.ErrSelfConnection:   codes.InvalidArgument,
+	application.ErrMessageTooLong:   codes.InvalidArgument,
+	application.ErrTooManyTargets:   codes.InvalidArgument,
+	application.ErrUnknownStrategy:  codes.InvalidArgument,
+	application.ErrNoDismissal:      codes.NotFound,
+	application.ErrUnauthenticated:  codes.Unauthenticated,
+	application.ErrNotActingUser:    codes.PermissionDenied,
+	application.ErrNotAdmin:         codes.PermissionDenied,
+	application.ErrNoBlock:          codes.NotFound,
+}
+
+// ErrorInterceptor turns errors returned by handlers into gRPC statuses. Domain errors get their code
+// and an ErrorInfo detail with their reason, errors that already are statuses pass through and anything
+// else is Internal.
+func ErrorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return resp, nil
+}
+
+func toStatusError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var domainErr *application.DomainError
+	if errors.As(err, &domainErr) {
+		code, found := domainErrorCodes[domainErr]
+		if !found {
+			code = codes.FailedPrecondition
+		}
+		return statusWithReason(code, err.Error(), domainErr.Reason)
+	}
+	if errors.Is(err, model.ErrConnectionNotFound) {
+		return toStatusError(application.ErrNotFound)
+	}
+	if errors.Is(err, model.ErrInvalidCursor) {
+		return statusWithReason(codes.InvalidArgument, err.Error(), "INVALID_CURSOR")
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func statusWithReason(code codes.Code, message string, reason string) error {
+	st := status.New(code, message)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: errorDomain})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
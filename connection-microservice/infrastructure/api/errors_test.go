@@ -0,0 +1,57 @@
+package api
+
+import (
+	"connection-microservice/application"
+	"connection-microservice/model"
+	"errors"
+	"fmt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestToStatusError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{name: "blocked", err: application.ErrBlocked, code: codes.PermissionDenied, reason: "BLOCKED"},
+		{name: "not pending", err: application.ErrNotPending, code: codes.FailedPrecondition, reason: "NOT_PENDING"},
+		{name: "already connected", err: application.ErrAlreadyConnected, code: codes.AlreadyExists, reason: "ALREADY_CONNECTED"},
+		{name: "self connection", err: application.ErrSelfConnection, code: codes.InvalidArgument, reason: "SELF_CONNECTION"},
+		{name: "unauthenticated", err: application.ErrUnauthenticated, code: codes.Unauthenticated, reason: "UNAUTHENTICATED"},
+		{name: "not acting user", err: application.ErrNotActingUser, code: codes.PermissionDenied, reason: "NOT_ACTING_USER"},
+		{name: "not admin", err: application.ErrNotAdmin, code: codes.PermissionDenied, reason: "NOT_ADMIN"},
+		{name: "wrapped domain error", err: fmt.Errorf("approve: %w", application.ErrNoBlock), code: codes.NotFound, reason: "NO_BLOCK"},
+		{name: "unmapped domain error", err: &application.DomainError{Reason: "OTHER", Message: "other"}, code: codes.FailedPrecondition, reason: "OTHER"},
+		{name: "connection not found", err: model.ErrConnectionNotFound, code: codes.NotFound, reason: "NOT_FOUND"},
+		{name: "invalid cursor", err: model.ErrInvalidCursor, code: codes.InvalidArgument, reason: "INVALID_CURSOR"},
+		{name: "status", err: status.Error(codes.Unavailable, "down"), code: codes.Unavailable},
+		{name: "unknown", err: errors.New("boom"), code: codes.Internal},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			st := status.Convert(toStatusError(test.err))
+			if st.Code() != test.code {
+				t.Fatalf("got code %v, want %v", st.Code(), test.code)
+			}
+
+			reason := ""
+			for _, detail := range st.Details() {
+				if info, ok := detail.(*errdetails.ErrorInfo); ok {
+					if info.Domain != errorDomain {
+						t.Fatalf("got domain %q, want %q", info.Domain, errorDomain)
+					}
+					reason = info.Reason
+				}
+			}
+			if reason != test.reason {
+				t.Fatalf("got reason %q, want %q", reason, test.reason)
+			}
+		})
+	}
+}
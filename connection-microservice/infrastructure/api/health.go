@@ -0,0 +1,122 @@
+package api
+
+import (
+	"connection-microservice/application"
+	"context"
+	"errors"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"time"
+)
+
+// Services reported by the health server. Liveness is SERVING for as long as the process runs, readiness and
+// the overall "" service only while every dependency check passes.
+const (
+	LivenessService  = "liveness"
+	ReadinessService = "readiness"
+)
+
+var errHealthCheckTimeout = errors.New("health check timed out")
+
+// HealthCheck reports whether a dependency the service needs to serve requests is usable.
+type HealthCheck struct {
+	Name  string
+	Check func() error
+}
+
+// HealthChecker drives the standard grpc.health.v1 service from periodic dependency checks.
+type HealthChecker struct {
+	server   *health.Server
+	checks   []HealthCheck
+	interval time.Duration
+	timeout  time.Duration
+	ready    bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func NewHealthChecker(checks []HealthCheck, interval time.Duration, timeout time.Duration) *HealthChecker {
+	server := health.NewServer()
+	server.SetServingStatus(LivenessService, healthpb.HealthCheckResponse_SERVING)
+	server.SetServingStatus(ReadinessService, healthpb.HealthCheckResponse_NOT_SERVING)
+	server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return &HealthChecker{
+		server:   server,
+		checks:   checks,
+		interval: interval,
+		timeout:  timeout,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+func (checker *HealthChecker) Server() healthpb.HealthServer {
+	return checker.server
+}
+
+func (checker *HealthChecker) Start() {
+	go func() {
+		defer close(checker.done)
+
+		ticker := time.NewTicker(checker.interval)
+		defer ticker.Stop()
+
+		checker.Check()
+		for {
+			select {
+			case <-ticker.C:
+				checker.Check()
+			case <-checker.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop reports every service as NOT_SERVING so no new requests are routed here while the server drains.
+func (checker *HealthChecker) Stop() {
+	close(checker.stop)
+	<-checker.done
+	checker.server.Shutdown()
+}
+
+// Check runs every dependency check concurrently and updates readiness. A check that does not answer within
+// the timeout counts as failed, so a hung dependency can not stall the checker.
+func (checker *HealthChecker) Check() {
+	results := make([]chan error, len(checker.checks))
+	for i, check := range checker.checks {
+		results[i] = make(chan error, 1)
+		go func(check HealthCheck, result chan<- error) {
+			result <- check.Check()
+		}(check, results[i])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checker.timeout)
+	defer cancel()
+
+	ready := true
+	for i, check := range checker.checks {
+		var err error
+		select {
+		case err = <-results[i]:
+		case <-ctx.Done():
+			err = errHealthCheckTimeout
+		}
+		if err != nil {
+			application.Log.Warn("Health check " + check.Name + " failed. Error: " + err.Error())
+			ready = false
+		}
+	}
+
+	if ready == checker.ready {
+		return
+	}
+	checker.ready = ready
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	application.Log.Info("Readiness changed to " + status.String())
+	checker.server.SetServingStatus(ReadinessService, status)
+	checker.server.SetServingStatus("", status)
+}
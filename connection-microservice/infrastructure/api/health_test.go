@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"errors"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"testing"
+	"time"
+)
+
+func readiness(t *testing.T, checker *HealthChecker) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := checker.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: ReadinessService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Status
+}
+
+func TestHealthCheckerCheck(t *testing.T) {
+	passing := HealthCheck{Name: "passing", Check: func() error { return nil }}
+	failing := HealthCheck{Name: "failing", Check: func() error { return errors.New("down") }}
+	hung := HealthCheck{Name: "hung", Check: func() error {
+		time.Sleep(time.Second)
+		return nil
+	}}
+
+	tests := []struct {
+		name   string
+		checks []HealthCheck
+		want   healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{name: "all passing", checks: []HealthCheck{passing, passing}, want: healthpb.HealthCheckResponse_SERVING},
+		{name: "one failing", checks: []HealthCheck{passing, failing}, want: healthpb.HealthCheckResponse_NOT_SERVING},
+		{name: "hung checks", checks: []HealthCheck{hung, passing, hung}, want: healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checker := NewHealthChecker(test.checks, time.Minute, 50*time.Millisecond)
+
+			start := time.Now()
+			checker.Check()
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Fatalf("Check took %v, want it bounded by the timeout", elapsed)
+			}
+			if got := readiness(t, checker); got != test.want {
+				t.Fatalf("got readiness %v, want %v", got, test.want)
+			}
+		})
+	}
+}
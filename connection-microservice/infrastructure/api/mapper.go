@@ -3,6 +3,8 @@ package api
 import (
 	"connection-microservice/model"
 	connectionService "github.com/XWS-BSEP-TIM1-2022/dislinkt/util/proto/connection"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
 )
 
 func mapConnection(connection *model.Connection) *connectionService.Connection {
@@ -14,6 +16,92 @@ func mapConnection(connection *model.Connection) *connectionService.Connection {
 		IsMessageNotificationEnabled: connection.IsMessageNotificationEnabled,
 		IsPostNotificationEnabled:    connection.IsPostNotificationEnabled,
 		IsCommentNotificationEnabled: connection.IsCommentNotificationEnabled,
+		CreatedAt:                    mapTime(connection.CreatedAt),
+		RequestedAt:                  mapTime(connection.RequestedAt),
+		ApprovedAt:                   mapTime(connection.ApprovedAt),
+		UpdatedAt:                    mapTime(connection.UpdatedAt),
+		ExpiredAt:                    mapTime(connection.ExpiredAt),
+		Message:                      connection.Message,
 	}
 	return connectionPb
 }
+
+func mapPageRequest(in *connectionService.UserIdPageRequest) model.PageRequest {
+	return model.PageRequest{
+		Cursor: in.Cursor,
+		Size:   int(in.PageSize),
+	}
+}
+
+func mapTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func mapDegree(degree *model.Degree) *connectionService.DegreeResponse {
+	return &connectionService.DegreeResponse{
+		TargetUserId: degree.UserId,
+		Degree:       int32(degree.Degree),
+		Path:         degree.Path,
+	}
+}
+
+func mapSuggestion(suggestion *model.Suggestion) *connectionService.Suggestion {
+	return &connectionService.Suggestion{
+		UserId:      suggestion.UserId,
+		Score:       suggestion.Score,
+		Reason:      suggestion.Reason,
+		MutualCount: int32(suggestion.MutualCount),
+		Explanation: suggestion.Explanation,
+		Strategy:    suggestion.Strategy,
+	}
+}
+
+func mapVariantStats(stats *model.VariantStats) *connectionService.VariantStats {
+	return &connectionService.VariantStats{
+		Experiment:     stats.Experiment,
+		Variant:        stats.Variant,
+		Impressions:    stats.Impressions,
+		Suggestions:    stats.Suggestions,
+		Acceptances:    stats.Acceptances,
+		AcceptanceRate: stats.AcceptanceRate,
+	}
+}
+
+func mapDismissal(dismissal *model.Dismissal) *connectionService.Dismissal {
+	return &connectionService.Dismissal{
+		UserId:          dismissal.UserId,
+		DismissedUserId: dismissal.DismissedUserId,
+		DismissedAt:     mapTime(dismissal.DismissedAt),
+		ExpiresAt:       mapTime(dismissal.ExpiresAt),
+	}
+}
+
+func mapBlock(block model.Block) *connectionService.Block {
+	return &connectionService.Block{
+		UserId:      block.UserId,
+		BlockUserId: block.BlockedUserId,
+	}
+}
+
+func mapConnections(connections []*model.Connection) []*connectionService.Connection {
+	connectionsPb := []*connectionService.Connection{}
+	for _, connection := range connections {
+		connectionsPb = append(connectionsPb, mapConnection(connection))
+	}
+	return connectionsPb
+}
+
+func mapNeighbourhood(neighbourhood *model.Neighbourhood) *connectionService.NeighbourhoodResponse {
+	return &connectionService.NeighbourhoodResponse{
+		UserId:           neighbourhood.UserId,
+		Followings:       mapConnections(neighbourhood.Followings),
+		Followers:        mapConnections(neighbourhood.Followers),
+		SentRequests:     mapConnections(neighbourhood.SentRequests),
+		ReceivedRequests: mapConnections(neighbourhood.ReceivedRequests),
+		Blocked:          neighbourhood.Blocked,
+		BlockedBy:        neighbourhood.BlockedBy,
+	}
+}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"connection-microservice/metrics"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// MetricsInterceptor records the latency and status code of every call. It runs outermost so it sees the
+// status the client gets.
+func MetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.ObserveRPC(info.FullMethod, status.Code(err).String(), time.Since(start).Seconds())
+	return resp, err
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	connectionService "github.com/XWS-BSEP-TIM1-2022/dislinkt/util/proto/connection"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ValidationInterceptor rejects malformed requests with InvalidArgument and a BadRequest detail listing
+// every field violation, before they reach a handler.
+func ValidationInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if violations := validateRequest(req); len(violations) > 0 {
+		return nil, invalidArgument(violations)
+	}
+	return handler(ctx, req)
+}
+
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field string, description string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+// userId requires a well-formed user id, user ids being hex encoded Mongo ObjectIDs.
+func (v *violations) userId(field string, id string) {
+	if id == "" {
+		v.add(field, "is required")
+	} else if !primitive.IsValidObjectID(id) {
+		v.add(field, "must be a 24 character hex ObjectID")
+	}
+}
+
+// distinctUsers requires both ids to name different users.
+func (v *violations) distinctUsers(field string, id string, otherId string) {
+	if id != "" && id == otherId {
+		v.add(field, "must differ from the acting user")
+	}
+}
+
+func (v *violations) notNegative(field string, value int32) {
+	if value < 0 {
+		v.add(field, "must not be negative")
+	}
+}
+
+func validateRequest(req interface{}) violations {
+	var v violations
+	switch in := req.(type) {
+	case *connectionService.UserConnectionRequest:
+		if in.Connection == nil {
+			v.add("connection", "is required")
+			break
+		}
+		v.userId("connection.user_id", in.Connection.UserId)
+		v.userId("connection.connected_user_id", in.Connection.ConnectedUserId)
+		v.distinctUsers("connection.connected_user_id", in.Connection.ConnectedUserId, in.Connection.UserId)
+	case *connectionService.Connection:
+		v.userId("user_id", in.UserId)
+		v.userId("connected_user_id", in.ConnectedUserId)
+		v.distinctUsers("connected_user_id", in.ConnectedUserId, in.UserId)
+	case *connectionService.UserIdRequest:
+		v.userId("user_id", in.UserId)
+	case *connectionService.UserIdPageRequest:
+		v.userId("user_id", in.UserId)
+		v.notNegative("page_size", in.PageSize)
+	case *connectionService.SuggestionsRequest:
+		v.userId("user_id", in.UserId)
+	case *connectionService.BlockUserRequest:
+		if in.Block == nil {
+			v.add("block", "is required")
+			break
+		}
+		v.userId("block.user_id", in.Block.UserId)
+		v.userId("block.block_user_id", in.Block.BlockUserId)
+		v.distinctUsers("block.block_user_id", in.Block.BlockUserId, in.Block.UserId)
+	case *connectionService.Block:
+		v.userId("user_id", in.UserId)
+		v.userId("block_user_id", in.BlockUserId)
+	case *connectionService.MutualConnectionsRequest:
+		v.userId("user_id", in.UserId)
+		v.userId("other_user_id", in.OtherUserId)
+		v.distinctUsers("other_user_id", in.OtherUserId, in.UserId)
+		v.notNegative("sample_size", in.SampleSize)
+	case *connectionService.DegreeRequest:
+		v.userId("user_id", in.UserId)
+		v.userId("target_user_id", in.TargetUserId)
+	case *connectionService.DegreesRequest:
+		v.userId("user_id", in.UserId)
+		for i, targetUserId := range in.TargetUserIds {
+			v.userId(fmt.Sprintf("target_user_ids[%d]", i), targetUserId)
+		}
+	case *connectionService.AllBlocksRequest:
+		v.notNegative("page_size", in.PageSize)
+	case *connectionService.DismissSuggestionRequest:
+		v.userId("user_id", in.UserId)
+		v.userId("dismissed_user_id", in.DismissedUserId)
+		v.distinctUsers("dismissed_user_id", in.DismissedUserId, in.UserId)
+	}
+	return v
+}
+
+func invalidArgument(v violations) error {
+	st := status.New(codes.InvalidArgument, "invalid request")
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
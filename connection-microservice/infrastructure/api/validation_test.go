@@ -0,0 +1,90 @@
+package api
+
+import (
+	connectionService "github.com/XWS-BSEP-TIM1-2022/dislinkt/util/proto/connection"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"reflect"
+	"testing"
+)
+
+const (
+	userId  = "62706d1b624b3da748f63fe3"
+	otherId = "62706d1b624b3da748f63fe4"
+)
+
+func TestValidateRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    interface{}
+		fields []string
+	}{
+		{
+			name: "valid connection request",
+			req:  &connectionService.UserConnectionRequest{Connection: &connectionService.Connection{UserId: userId, ConnectedUserId: otherId}},
+		},
+		{
+			name:   "nil connection",
+			req:    &connectionService.UserConnectionRequest{},
+			fields: []string{"connection"},
+		},
+		{
+			name:   "bad object id",
+			req:    &connectionService.UserConnectionRequest{Connection: &connectionService.Connection{UserId: "not-an-id", ConnectedUserId: otherId}},
+			fields: []string{"connection.user_id"},
+		},
+		{
+			name:   "missing ids",
+			req:    &connectionService.Connection{},
+			fields: []string{"user_id", "connected_user_id"},
+		},
+		{
+			name:   "self target",
+			req:    &connectionService.UserConnectionRequest{Connection: &connectionService.Connection{UserId: userId, ConnectedUserId: userId}},
+			fields: []string{"connection.connected_user_id"},
+		},
+		{
+			name:   "negative page size",
+			req:    &connectionService.UserIdPageRequest{UserId: userId, PageSize: -1},
+			fields: []string{"page_size"},
+		},
+		{
+			name:   "suggestions for bad object id",
+			req:    &connectionService.SuggestionsRequest{UserId: "not-an-id", Strategy: "jaccard"},
+			fields: []string{"user_id"},
+		},
+		{
+			name: "unvalidated request",
+			req:  &connectionService.EmptyRequest{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var fields []string
+			for _, violation := range validateRequest(test.req) {
+				fields = append(fields, violation.Field)
+			}
+			if !reflect.DeepEqual(fields, test.fields) {
+				t.Fatalf("got violations of %v, want %v", fields, test.fields)
+			}
+		})
+	}
+}
+
+func TestInvalidArgumentListsViolations(t *testing.T) {
+	v := validateRequest(&connectionService.UserIdPageRequest{PageSize: -1})
+
+	st := status.Convert(invalidArgument(v))
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("got %v, want InvalidArgument", st.Code())
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("got details %v, want one BadRequest", st.Details())
+	}
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(badRequest.FieldViolations) != 2 {
+		t.Fatalf("got %v, want a BadRequest with two violations", st.Details()[0])
+	}
+}
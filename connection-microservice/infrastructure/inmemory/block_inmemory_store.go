@@ -0,0 +1,136 @@
+package inmemory
+
+import (
+	"connection-microservice/model"
+	"context"
+	"sort"
+)
+
+type BlockInMemoryStore struct {
+	graph *Graph
+}
+
+func NewBlockInMemoryStore(graph *Graph) model.BlockStore {
+	return &BlockInMemoryStore{
+		graph: graph,
+	}
+}
+
+func (store *BlockInMemoryStore) BlockUser(ctx context.Context, block model.Block) error {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	store.graph.mergeUser(block.UserId)
+	store.graph.mergeUser(block.BlockedUserId)
+
+	if store.graph.findBlock(block.UserId, block.BlockedUserId) < 0 {
+		store.graph.blocks = append(store.graph.blocks, block)
+	}
+	return nil
+}
+
+func (store *BlockInMemoryStore) BlockAndSever(ctx context.Context, block model.Block) ([]*model.Connection, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	store.graph.mergeUser(block.UserId)
+	store.graph.mergeUser(block.BlockedUserId)
+
+	if store.graph.findBlock(block.UserId, block.BlockedUserId) < 0 {
+		store.graph.blocks = append(store.graph.blocks, block)
+	}
+
+	var removed []*model.Connection
+	var kept []*model.Connection
+	for _, connection := range store.graph.connections {
+		if (connection.UserId == block.UserId && connection.ConnectedUserId == block.BlockedUserId) ||
+			(connection.UserId == block.BlockedUserId && connection.ConnectedUserId == block.UserId) {
+			removed = append(removed, connection)
+		} else {
+			kept = append(kept, connection)
+		}
+	}
+	store.graph.connections = kept
+
+	return removed, nil
+}
+
+func (store *BlockInMemoryStore) UnblockUser(ctx context.Context, block model.Block) error {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	if i := store.graph.findBlock(block.UserId, block.BlockedUserId); i >= 0 {
+		store.graph.blocks = append(store.graph.blocks[:i], store.graph.blocks[i+1:]...)
+	}
+	return nil
+}
+
+func (store *BlockInMemoryStore) IsBlocked(ctx context.Context, block model.Block) (bool, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	return store.graph.findBlock(block.UserId, block.BlockedUserId) >= 0, nil
+}
+
+func (store *BlockInMemoryStore) GetBlocked(ctx context.Context, userId string) ([]string, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var blockedUserIds []string
+	for _, block := range store.graph.blocks {
+		if block.UserId == userId {
+			blockedUserIds = append(blockedUserIds, block.BlockedUserId)
+		}
+	}
+	return blockedUserIds, nil
+}
+
+func (store *BlockInMemoryStore) GetBlockedBy(ctx context.Context, userId string) ([]string, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var blockedUserIds []string
+	for _, block := range store.graph.blocks {
+		if block.BlockedUserId == userId {
+			blockedUserIds = append(blockedUserIds, block.UserId)
+		}
+	}
+	return blockedUserIds, nil
+}
+
+func (store *BlockInMemoryStore) GetBlockedPage(ctx context.Context, userId string, page model.PageRequest) (*model.UserIdPage, error) {
+	blockedUserIds, _ := store.GetBlocked(ctx, userId)
+	return userIdsPage(blockedUserIds, page)
+}
+
+func (store *BlockInMemoryStore) GetBlockedByPage(ctx context.Context, userId string, page model.PageRequest) (*model.UserIdPage, error) {
+	blockedUserIds, _ := store.GetBlockedBy(ctx, userId)
+	return userIdsPage(blockedUserIds, page)
+}
+
+func (store *BlockInMemoryStore) GetAllBlocksPage(ctx context.Context, page model.PageRequest) (*model.BlockPage, error) {
+	after, err := model.DecodeCursor(page.Cursor, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	store.graph.lock.RLock()
+	blocks := make([]model.Block, len(store.graph.blocks))
+	copy(blocks, store.graph.blocks)
+	store.graph.lock.RUnlock()
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return compareKeys(blockKey(blocks[i]), blockKey(blocks[j])) < 0
+	})
+
+	var selected []model.Block
+	for _, block := range blocks {
+		if len(selected) > page.Size {
+			break
+		}
+		if compareKeys(blockKey(block), after) > 0 {
+			selected = append(selected, block)
+		}
+	}
+	return model.NewBlockPage(selected, page.Size), nil
+}
@@ -0,0 +1,538 @@
+package inmemory
+
+import (
+	"connection-microservice/model"
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+type ConnectionInMemoryStore struct {
+	graph *Graph
+}
+
+func NewConnectionInMemoryStore(graph *Graph) model.ConnectionStore {
+	return &ConnectionInMemoryStore{
+		graph: graph,
+	}
+}
+
+// CreateConnection keeps at most one CONNECT edge per ordered pair of users; creating it again resets it.
+func (store *ConnectionInMemoryStore) CreateConnection(ctx context.Context, connection *model.Connection) (*model.Connection, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	store.graph.mergeUser(connection.UserId)
+	store.graph.mergeUser(connection.ConnectedUserId)
+
+	connection.IsMessageNotificationEnabled = true
+	connection.IsPostNotificationEnabled = true
+	connection.IsCommentNotificationEnabled = true
+
+	now := time.Now().UTC()
+	connection.CreatedAt = now
+	connection.UpdatedAt = now
+	if connection.PendingConnection {
+		connection.RequestedAt = now
+	}
+	if connection.IsConnected {
+		connection.ApprovedAt = now
+	}
+
+	if i := store.graph.findConnection(connection.UserId, connection.ConnectedUserId); i >= 0 {
+		store.graph.connections[i] = copyConnection(connection)
+	} else {
+		store.graph.connections = append(store.graph.connections, copyConnection(connection))
+	}
+
+	return connection, nil
+}
+
+func (store *ConnectionInMemoryStore) UpdateConnection(ctx context.Context, connection *model.Connection) (*model.Connection, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	connection.UpdatedAt = time.Now().UTC()
+
+	i := store.graph.findConnection(connection.UserId, connection.ConnectedUserId)
+	if i < 0 {
+		return nil, model.ErrConnectionNotFound
+	}
+
+	existing := store.graph.connections[i]
+	connection.CreatedAt = existing.CreatedAt
+	connection.RequestedAt = existing.RequestedAt
+	if connection.ApprovedAt.IsZero() {
+		connection.ApprovedAt = existing.ApprovedAt
+	}
+	store.graph.connections[i] = copyConnection(connection)
+
+	return connection, nil
+}
+
+func (store *ConnectionInMemoryStore) DeleteConnection(ctx context.Context, userId string, connectedUserId string) error {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	i := store.graph.findConnection(userId, connectedUserId)
+	if i < 0 {
+		return model.ErrConnectionNotFound
+	}
+	store.graph.connections = append(store.graph.connections[:i], store.graph.connections[i+1:]...)
+	return nil
+}
+
+func (store *ConnectionInMemoryStore) DeletePendingConnection(ctx context.Context, userId string, connectedUserId string) (bool, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	i := store.graph.findConnection(userId, connectedUserId)
+	if i < 0 || store.graph.connections[i].IsConnected || !store.graph.connections[i].PendingConnection {
+		return false, nil
+	}
+	store.graph.connections = append(store.graph.connections[:i], store.graph.connections[i+1:]...)
+	return true, nil
+}
+
+// GetConnectionByUsersId returns ErrConnectionNotFound when there is no edge, like the Neo4j store.
+func (store *ConnectionInMemoryStore) GetConnectionByUsersId(ctx context.Context, userId string, connectedUserId string) (*model.Connection, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	if i := store.graph.findConnection(userId, connectedUserId); i >= 0 {
+		return copyConnection(store.graph.connections[i]), nil
+	}
+	return nil, model.ErrConnectionNotFound
+}
+
+func (store *ConnectionInMemoryStore) GetAllConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
+	connections := store.getConnections(func(connection *model.Connection) bool {
+		return connection.UserId == userId && connection.ExpiredAt.IsZero()
+	})
+	newConnections := store.getConnections(func(connection *model.Connection) bool {
+		return connection.ConnectedUserId == userId && connection.ExpiredAt.IsZero()
+	})
+
+	connections = append(connections, newConnections...)
+
+	return connections, nil
+}
+
+func (store *ConnectionInMemoryStore) GetFollowings(ctx context.Context, userId string) ([]*model.Connection, error) {
+	return store.getConnections(func(connection *model.Connection) bool {
+		return connection.UserId == userId && connection.IsConnected
+	}), nil
+}
+
+func (store *ConnectionInMemoryStore) GetFollowers(ctx context.Context, connectedUserId string) ([]*model.Connection, error) {
+	return store.getConnections(func(connection *model.Connection) bool {
+		return connection.ConnectedUserId == connectedUserId && connection.IsConnected
+	}), nil
+}
+
+func (store *ConnectionInMemoryStore) GetAllRequestConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
+	return store.getConnections(func(connection *model.Connection) bool {
+		return connection.ConnectedUserId == userId && !connection.IsConnected && connection.PendingConnection
+	}), nil
+}
+
+func (store *ConnectionInMemoryStore) GetAllPendingConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
+	return store.getConnections(func(connection *model.Connection) bool {
+		return connection.UserId == userId && !connection.IsConnected && connection.PendingConnection
+	}), nil
+}
+
+func (store *ConnectionInMemoryStore) GetAllConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	connections := store.getConnections(func(connection *model.Connection) bool {
+		return (connection.UserId == userId || connection.ConnectedUserId == userId) && connection.ExpiredAt.IsZero()
+	})
+	return connectionsPage(connections, page, 2, pairKey)
+}
+
+func (store *ConnectionInMemoryStore) GetFollowingsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	connections, _ := store.GetFollowings(ctx, userId)
+	return connectionsPage(connections, page, 1, connectedUserKey)
+}
+
+func (store *ConnectionInMemoryStore) GetFollowersPage(ctx context.Context, connectedUserId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	connections, _ := store.GetFollowers(ctx, connectedUserId)
+	return connectionsPage(connections, page, 1, userKey)
+}
+
+func (store *ConnectionInMemoryStore) GetAllRequestConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	connections, _ := store.GetAllRequestConnectionsByUserId(ctx, userId)
+	return connectionsPage(connections, page, 1, userKey)
+}
+
+func (store *ConnectionInMemoryStore) GetAllPendingConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	connections, _ := store.GetAllPendingConnectionsByUserId(ctx, userId)
+	return connectionsPage(connections, page, 1, connectedUserKey)
+}
+
+func (store *ConnectionInMemoryStore) GetAllExpiredConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	connections := store.getConnections(func(connection *model.Connection) bool {
+		return connection.UserId == userId && !connection.IsConnected && !connection.PendingConnection && !connection.ExpiredAt.IsZero()
+	})
+	return connectionsPage(connections, page, 1, connectedUserKey)
+}
+
+func (store *ConnectionInMemoryStore) ExpirePendingConnections(ctx context.Context, requestedBefore time.Time) ([]*model.Connection, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	now := time.Now().UTC()
+	var expired []*model.Connection
+	for _, connection := range store.graph.connections {
+		if !connection.IsConnected && connection.PendingConnection && connection.RequestedAt.Before(requestedBefore) {
+			connection.PendingConnection = false
+			connection.ExpiredAt = now
+			connection.UpdatedAt = now
+			expired = append(expired, copyConnection(connection))
+		}
+	}
+	return expired, nil
+}
+
+func (store *ConnectionInMemoryStore) CountPendingConnections(ctx context.Context, requestedBefore time.Time) (int64, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var pending int64
+	for _, connection := range store.graph.connections {
+		if !connection.IsConnected && connection.PendingConnection && connection.RequestedAt.Before(requestedBefore) {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+func (store *ConnectionInMemoryStore) GetMutualConnections(ctx context.Context, userId string, otherUserId string, excludedUserIds []string, sampleSize int) (*model.MutualConnections, error) {
+	excluded := map[string]bool{}
+	for _, id := range excludedUserIds {
+		excluded[id] = true
+	}
+
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var mutualIds []string
+	for _, connection := range store.graph.connections {
+		if connection.UserId == userId && connection.IsConnected && !excluded[connection.ConnectedUserId] &&
+			store.graph.isFollowing(otherUserId, connection.ConnectedUserId) {
+			mutualIds = append(mutualIds, connection.ConnectedUserId)
+		}
+	}
+	sort.Strings(mutualIds)
+
+	mutual := &model.MutualConnections{Count: int64(len(mutualIds)), UserIds: []string{}}
+	if len(mutualIds) > sampleSize {
+		mutualIds = mutualIds[:sampleSize]
+	}
+	mutual.UserIds = append(mutual.UserIds, mutualIds...)
+	return mutual, nil
+}
+
+func (store *ConnectionInMemoryStore) GetDegrees(ctx context.Context, userId string, targetUserIds []string, excludedUserIds []string, maxDepth int, withPath bool) ([]*model.Degree, error) {
+	excluded := map[string]bool{}
+	for _, id := range excludedUserIds {
+		excluded[id] = true
+	}
+
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	if !store.graph.userIds[userId] {
+		return nil, nil
+	}
+
+	// breadth-first search outwards from userId, remembering through whom every user was reached first
+	previous := map[string]string{userId: ""}
+	depth := map[string]int{userId: 0}
+	frontier := []string{userId}
+	for d := 1; d <= maxDepth && len(frontier) > 0; d++ {
+		var next []string
+		for _, from := range frontier {
+			if from != userId && excluded[from] {
+				continue
+			}
+			for _, connection := range store.graph.connections {
+				if connection.UserId != from || !connection.IsConnected {
+					continue
+				}
+				if _, seen := previous[connection.ConnectedUserId]; seen {
+					continue
+				}
+				previous[connection.ConnectedUserId] = from
+				depth[connection.ConnectedUserId] = d
+				next = append(next, connection.ConnectedUserId)
+			}
+		}
+		frontier = next
+	}
+
+	var degrees []*model.Degree
+	for _, targetUserId := range targetUserIds {
+		if targetUserId == userId || !store.graph.userIds[targetUserId] {
+			continue
+		}
+		degree := &model.Degree{UserId: targetUserId}
+		if _, reached := previous[targetUserId]; reached {
+			degree.Degree = depth[targetUserId]
+			if withPath {
+				for id := targetUserId; id != ""; id = previous[id] {
+					degree.Path = append([]string{id}, degree.Path...)
+				}
+			}
+		}
+		degrees = append(degrees, degree)
+	}
+	return degrees, nil
+}
+
+func (store *ConnectionInMemoryStore) GetFollowingsOfMyFollowings(ctx context.Context, connectedUserId string, userId string) ([]string, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var retVal []string
+	for _, connection := range store.graph.connections {
+		if len(retVal) == 10 {
+			break
+		}
+		if connection.UserId == connectedUserId && connection.IsConnected &&
+			!store.graph.isFollowing(userId, connection.ConnectedUserId) {
+			retVal = append(retVal, connection.ConnectedUserId)
+		}
+	}
+	return retVal, nil
+}
+
+func (store *ConnectionInMemoryStore) GetSuggestionCandidates(ctx context.Context, userId string, limit int) ([]*model.SuggestionCandidate, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	followings := map[string]bool{}
+	excluded := map[string]bool{userId: true}
+	for _, connection := range store.graph.connections {
+		if connection.UserId == userId && connection.ExpiredAt.IsZero() {
+			excluded[connection.ConnectedUserId] = true
+			if connection.IsConnected {
+				followings[connection.ConnectedUserId] = true
+			}
+		}
+	}
+	for _, block := range store.graph.blocks {
+		if block.UserId == userId {
+			excluded[block.BlockedUserId] = true
+		}
+		if block.BlockedUserId == userId {
+			excluded[block.UserId] = true
+		}
+	}
+	now := time.Now()
+	for _, dismissal := range store.graph.dismissals {
+		if dismissal.UserId == userId && dismissal.ExpiresAt.After(now) {
+			excluded[dismissal.DismissedUserId] = true
+		}
+	}
+
+	degrees, followerCounts := store.graph.degrees()
+
+	byId := map[string]*model.SuggestionCandidate{}
+	var candidates []*model.SuggestionCandidate
+	for _, connection := range store.graph.connections {
+		if !followings[connection.UserId] || !connection.IsConnected || excluded[connection.ConnectedUserId] {
+			continue
+		}
+		candidate, found := byId[connection.ConnectedUserId]
+		if !found {
+			candidate = &model.SuggestionCandidate{UserId: connection.ConnectedUserId, FollowerCount: followerCounts[connection.ConnectedUserId]}
+			byId[connection.ConnectedUserId] = candidate
+			candidates = append(candidates, candidate)
+		}
+		candidate.MutualCount++
+		candidate.AdamicAdar += 1 / math.Log(1+float64(degrees[connection.UserId]))
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].MutualCount != candidates[j].MutualCount {
+			return candidates[i].MutualCount > candidates[j].MutualCount
+		}
+		return candidates[i].UserId < candidates[j].UserId
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+func (store *ConnectionInMemoryStore) GetPopularUsers(ctx context.Context, userId string, limit int) ([]*model.SuggestionCandidate, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	_, followerCounts := store.graph.degrees()
+	now := time.Now()
+
+	var candidates []*model.SuggestionCandidate
+	for _, user := range store.graph.users {
+		if store.graph.isExcludedFromSuggestions(userId, user, now) {
+			continue
+		}
+		candidates = append(candidates, &model.SuggestionCandidate{UserId: user, FollowerCount: followerCounts[user]})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].FollowerCount != candidates[j].FollowerCount {
+			return candidates[i].FollowerCount > candidates[j].FollowerCount
+		}
+		return candidates[i].UserId < candidates[j].UserId
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+func (store *ConnectionInMemoryStore) GetMostFollowedUsers(ctx context.Context, limit int) ([]*model.SuggestionCandidate, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	_, followerCounts := store.graph.degrees()
+
+	var candidates []*model.SuggestionCandidate
+	for _, user := range store.graph.users {
+		candidates = append(candidates, &model.SuggestionCandidate{UserId: user, FollowerCount: followerCounts[user]})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].FollowerCount != candidates[j].FollowerCount {
+			return candidates[i].FollowerCount > candidates[j].FollowerCount
+		}
+		return candidates[i].UserId < candidates[j].UserId
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+func (store *ConnectionInMemoryStore) GetRecentlyActiveUsers(ctx context.Context, limit int) ([]string, error) {
+	if limit < 0 {
+		return nil, errors.New("limit must be a non-negative integer")
+	}
+
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	lastActiveAt := map[string]time.Time{}
+	var userIds []string
+	for _, connection := range store.graph.connections {
+		last, found := lastActiveAt[connection.UserId]
+		if !found {
+			userIds = append(userIds, connection.UserId)
+		}
+		if !found || connection.UpdatedAt.After(last) {
+			lastActiveAt[connection.UserId] = connection.UpdatedAt
+		}
+	}
+
+	sort.Slice(userIds, func(i, j int) bool {
+		if !lastActiveAt[userIds[i]].Equal(lastActiveAt[userIds[j]]) {
+			return lastActiveAt[userIds[i]].After(lastActiveAt[userIds[j]])
+		}
+		return userIds[i] < userIds[j]
+	})
+	if len(userIds) > limit {
+		userIds = userIds[:limit]
+	}
+	return userIds, nil
+}
+
+// GetRandomUsers samples every user with model.SampleUserIds like the Neo4j store.
+func (store *ConnectionInMemoryStore) GetRandomUsers(ctx context.Context, limit int, seed int64) ([]string, error) {
+	if limit < 0 {
+		return nil, errors.New("limit must be a non-negative integer")
+	}
+
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	userIds := append([]string{}, store.graph.users...)
+	return model.SampleUserIds(userIds, limit, seed), nil
+}
+
+func (store *ConnectionInMemoryStore) GetSuggestionExclusions(ctx context.Context, userId string) ([]string, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	now := time.Now()
+	var userIds []string
+	for _, user := range store.graph.users {
+		if user != userId && store.graph.isExcludedFromSuggestions(userId, user, now) {
+			userIds = append(userIds, user)
+		}
+	}
+	return userIds, nil
+}
+
+func (store *ConnectionInMemoryStore) DeleteUserEdges(ctx context.Context, userId string) (int64, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	var deleted int64
+	var connections []*model.Connection
+	for _, connection := range store.graph.connections {
+		if connection.UserId == userId || connection.ConnectedUserId == userId {
+			deleted++
+		} else {
+			connections = append(connections, connection)
+		}
+	}
+	store.graph.connections = connections
+
+	var blocks []model.Block
+	for _, block := range store.graph.blocks {
+		if block.UserId == userId || block.BlockedUserId == userId {
+			deleted++
+		} else {
+			blocks = append(blocks, block)
+		}
+	}
+	store.graph.blocks = blocks
+
+	var dismissals []*model.Dismissal
+	for _, dismissal := range store.graph.dismissals {
+		if dismissal.UserId == userId || dismissal.DismissedUserId == userId {
+			deleted++
+		} else {
+			dismissals = append(dismissals, dismissal)
+		}
+	}
+	store.graph.dismissals = dismissals
+
+	var impressions []*model.SuggestionImpression
+	for _, impression := range store.graph.impressions {
+		if impression.UserId == userId || impression.SuggestedUserId == userId {
+			deleted++
+		} else {
+			impressions = append(impressions, impression)
+		}
+	}
+	store.graph.impressions = impressions
+
+	return deleted, nil
+}
+
+func (store *ConnectionInMemoryStore) getConnections(filter func(connection *model.Connection) bool) []*model.Connection {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var connections []*model.Connection
+	for _, connection := range store.graph.connections {
+		if filter(connection) {
+			connections = append(connections, copyConnection(connection))
+		}
+	}
+	return connections
+}
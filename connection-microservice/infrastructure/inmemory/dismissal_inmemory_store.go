@@ -0,0 +1,104 @@
+package inmemory
+
+import (
+	"connection-microservice/model"
+	"context"
+	"sort"
+	"time"
+)
+
+type DismissalInMemoryStore struct {
+	graph *Graph
+}
+
+func NewDismissalInMemoryStore(graph *Graph) model.DismissalStore {
+	return &DismissalInMemoryStore{
+		graph: graph,
+	}
+}
+
+func (store *DismissalInMemoryStore) DismissSuggestion(ctx context.Context, dismissal model.Dismissal) error {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	store.graph.mergeUser(dismissal.UserId)
+	store.graph.mergeUser(dismissal.DismissedUserId)
+
+	for _, stored := range store.graph.dismissals {
+		if stored.UserId == dismissal.UserId && stored.DismissedUserId == dismissal.DismissedUserId {
+			stored.DismissedAt = dismissal.DismissedAt
+			stored.ExpiresAt = dismissal.ExpiresAt
+			return nil
+		}
+	}
+	store.graph.dismissals = append(store.graph.dismissals, &dismissal)
+	return nil
+}
+
+func (store *DismissalInMemoryStore) UndoDismissal(ctx context.Context, userId string, dismissedUserId string) (bool, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	for i, dismissal := range store.graph.dismissals {
+		if dismissal.UserId == userId && dismissal.DismissedUserId == dismissedUserId {
+			store.graph.dismissals = append(store.graph.dismissals[:i], store.graph.dismissals[i+1:]...)
+			return dismissal.ExpiresAt.After(time.Now()), nil
+		}
+	}
+	return false, nil
+}
+
+func (store *DismissalInMemoryStore) GetDismissalsPage(ctx context.Context, userId string, page model.PageRequest) (*model.DismissalPage, error) {
+	after, err := model.DecodeCursor(page.Cursor, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var dismissals []*model.Dismissal
+	now := time.Now()
+	for _, dismissal := range store.graph.dismissals {
+		if dismissal.UserId == userId && dismissal.ExpiresAt.After(now) && dismissal.DismissedUserId > after[0] {
+			copied := *dismissal
+			dismissals = append(dismissals, &copied)
+		}
+	}
+	sort.Slice(dismissals, func(i, j int) bool {
+		return dismissals[i].DismissedUserId < dismissals[j].DismissedUserId
+	})
+	if len(dismissals) > page.Size+1 {
+		dismissals = dismissals[:page.Size+1]
+	}
+	return model.NewDismissalPage(dismissals, page.Size), nil
+}
+
+func (store *DismissalInMemoryStore) GetDismissedUserIds(ctx context.Context, userId string) ([]string, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var userIds []string
+	now := time.Now()
+	for _, dismissal := range store.graph.dismissals {
+		if dismissal.UserId == userId && dismissal.ExpiresAt.After(now) {
+			userIds = append(userIds, dismissal.DismissedUserId)
+		}
+	}
+	return userIds, nil
+}
+
+func (store *DismissalInMemoryStore) DeleteExpiredDismissals(ctx context.Context, now time.Time) (int64, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	var kept []*model.Dismissal
+	for _, dismissal := range store.graph.dismissals {
+		if dismissal.ExpiresAt.After(now) {
+			kept = append(kept, dismissal)
+		}
+	}
+	deleted := int64(len(store.graph.dismissals) - len(kept))
+	store.graph.dismissals = kept
+	return deleted, nil
+}
@@ -0,0 +1,103 @@
+package inmemory
+
+import (
+	"connection-microservice/model"
+	"sync"
+	"time"
+)
+
+// Graph is the shared state behind the in-memory stores. Like a Neo4j
+// database it is created once and handed to every store, so users created
+// through a BLOCK are visible to connection queries and vice versa.
+// Users, CONNECT edges and BLOCK edges are kept in insertion order, which
+// stands in for the scan order Neo4j uses when a query has no ORDER BY.
+type Graph struct {
+	lock        sync.RWMutex
+	users       []string
+	userIds     map[string]bool
+	connections []*model.Connection
+	blocks      []model.Block
+	impressions []*model.SuggestionImpression
+	dismissals  []*model.Dismissal
+}
+
+func NewGraph() *Graph {
+	return &Graph{
+		userIds: map[string]bool{},
+	}
+}
+
+// mergeUser mirrors MERGE (user:User {userId:$userId}). Callers must hold the write lock.
+func (graph *Graph) mergeUser(userId string) {
+	if graph.userIds[userId] {
+		return
+	}
+	graph.userIds[userId] = true
+	graph.users = append(graph.users, userId)
+}
+
+// findConnection returns the index of the CONNECT edge userId->connectedUserId or -1.
+// Callers must hold the lock.
+func (graph *Graph) findConnection(userId string, connectedUserId string) int {
+	for i, connection := range graph.connections {
+		if connection.UserId == userId && connection.ConnectedUserId == connectedUserId {
+			return i
+		}
+	}
+	return -1
+}
+
+// findBlock returns the index of the BLOCK edge userId->blockedUserId or -1.
+// Callers must hold the lock.
+func (graph *Graph) findBlock(userId string, blockedUserId string) int {
+	for i, block := range graph.blocks {
+		if block.UserId == userId && block.BlockedUserId == blockedUserId {
+			return i
+		}
+	}
+	return -1
+}
+
+func (graph *Graph) isFollowing(userId string, connectedUserId string) bool {
+	i := graph.findConnection(userId, connectedUserId)
+	return i >= 0 && graph.connections[i].IsConnected
+}
+
+// degrees counts approved followings and followers of every user. Callers must hold the lock.
+func (graph *Graph) degrees() (map[string]int, map[string]int) {
+	followings := map[string]int{}
+	followers := map[string]int{}
+	for _, connection := range graph.connections {
+		if connection.IsConnected {
+			followings[connection.UserId]++
+			followers[connection.ConnectedUserId]++
+		}
+	}
+	return followings, followers
+}
+
+// isDismissed reports whether userId dismissed dismissedUserId and the dismissal has not expired at now.
+// Callers must hold the lock.
+func (graph *Graph) isDismissed(userId string, dismissedUserId string, now time.Time) bool {
+	for _, dismissal := range graph.dismissals {
+		if dismissal.UserId == userId && dismissal.DismissedUserId == dismissedUserId {
+			return dismissal.ExpiresAt.After(now)
+		}
+	}
+	return false
+}
+
+// isExcludedFromSuggestions mirrors the exclusions of suggestion queries: the user itself, users with a
+// connection from the user that has not expired, users blocked by or blocking the user and users the user
+// dismissed. Callers must hold the lock.
+func (graph *Graph) isExcludedFromSuggestions(userId string, candidate string, now time.Time) bool {
+	i := graph.findConnection(userId, candidate)
+	return candidate == userId || (i >= 0 && graph.connections[i].ExpiredAt.IsZero()) ||
+		graph.findBlock(userId, candidate) >= 0 || graph.findBlock(candidate, userId) >= 0 ||
+		graph.isDismissed(userId, candidate, now)
+}
+
+func copyConnection(connection *model.Connection) *model.Connection {
+	c := *connection
+	return &c
+}
@@ -0,0 +1,107 @@
+package inmemory
+
+import (
+	"connection-microservice/model"
+	"context"
+	"sort"
+	"time"
+)
+
+type ImpressionInMemoryStore struct {
+	graph *Graph
+}
+
+func NewImpressionInMemoryStore(graph *Graph) model.ImpressionStore {
+	return &ImpressionInMemoryStore{
+		graph: graph,
+	}
+}
+
+func (store *ImpressionInMemoryStore) RecordImpressions(ctx context.Context, impressions []*model.SuggestionImpression) error {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	for _, impression := range impressions {
+		store.graph.mergeUser(impression.UserId)
+		store.graph.mergeUser(impression.SuggestedUserId)
+
+		stored := store.findImpression(impression)
+		if stored == nil {
+			stored = &model.SuggestionImpression{
+				UserId:          impression.UserId,
+				SuggestedUserId: impression.SuggestedUserId,
+				Experiment:      impression.Experiment,
+				Variant:         impression.Variant,
+			}
+			store.graph.impressions = append(store.graph.impressions, stored)
+		}
+		stored.Count++
+		stored.LastShownAt = impression.LastShownAt
+	}
+	return nil
+}
+
+func (store *ImpressionInMemoryStore) AcceptImpression(ctx context.Context, userId string, suggestedUserId string, shownAfter time.Time, acceptedAt time.Time) (*model.SuggestionImpression, error) {
+	store.graph.lock.Lock()
+	defer store.graph.lock.Unlock()
+
+	var latest *model.SuggestionImpression
+	for _, impression := range store.graph.impressions {
+		if impression.UserId != userId || impression.SuggestedUserId != suggestedUserId ||
+			!impression.AcceptedAt.IsZero() || impression.LastShownAt.Before(shownAfter) {
+			continue
+		}
+		if latest == nil || impression.LastShownAt.After(latest.LastShownAt) {
+			latest = impression
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	latest.AcceptedAt = acceptedAt
+	accepted := *latest
+	return &accepted, nil
+}
+
+func (store *ImpressionInMemoryStore) GetVariantStats(ctx context.Context, experiment string) ([]*model.VariantStats, error) {
+	store.graph.lock.RLock()
+	defer store.graph.lock.RUnlock()
+
+	var variants []string
+	impressions := map[string]int64{}
+	suggestions := map[string]int64{}
+	acceptances := map[string]int64{}
+	for _, impression := range store.graph.impressions {
+		if impression.Experiment != experiment {
+			continue
+		}
+		if _, found := suggestions[impression.Variant]; !found {
+			variants = append(variants, impression.Variant)
+		}
+		impressions[impression.Variant] += impression.Count
+		suggestions[impression.Variant]++
+		if !impression.AcceptedAt.IsZero() {
+			acceptances[impression.Variant]++
+		}
+	}
+	sort.Strings(variants)
+
+	var stats []*model.VariantStats
+	for _, variant := range variants {
+		stats = append(stats, model.NewVariantStats(experiment, variant, impressions[variant], suggestions[variant], acceptances[variant]))
+	}
+	return stats, nil
+}
+
+// findImpression returns the stored impression with the same users, experiment and variant or nil.
+// Callers must hold the lock.
+func (store *ImpressionInMemoryStore) findImpression(impression *model.SuggestionImpression) *model.SuggestionImpression {
+	for _, stored := range store.graph.impressions {
+		if stored.UserId == impression.UserId && stored.SuggestedUserId == impression.SuggestedUserId &&
+			stored.Experiment == impression.Experiment && stored.Variant == impression.Variant {
+			return stored
+		}
+	}
+	return nil
+}
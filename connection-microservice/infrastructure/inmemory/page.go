@@ -0,0 +1,78 @@
+package inmemory
+
+import (
+	"connection-microservice/model"
+	"sort"
+)
+
+func userKey(connection *model.Connection) []string {
+	return []string{connection.UserId}
+}
+
+func connectedUserKey(connection *model.Connection) []string {
+	return []string{connection.ConnectedUserId}
+}
+
+func pairKey(connection *model.Connection) []string {
+	return []string{connection.UserId, connection.ConnectedUserId}
+}
+
+func blockKey(block model.Block) []string {
+	return []string{block.UserId, block.BlockedUserId}
+}
+
+func compareKeys(a []string, b []string) int {
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// connectionsPage sorts connections by key and returns the page following page.Cursor.
+func connectionsPage(connections []*model.Connection, page model.PageRequest, keyLength int, key func(connection *model.Connection) []string) (*model.ConnectionPage, error) {
+	after, err := model.DecodeCursor(page.Cursor, keyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(connections, func(i, j int) bool {
+		return compareKeys(key(connections[i]), key(connections[j])) < 0
+	})
+
+	var selected []*model.Connection
+	for _, connection := range connections {
+		if len(selected) > page.Size {
+			break
+		}
+		if compareKeys(key(connection), after) > 0 {
+			selected = append(selected, connection)
+		}
+	}
+	return model.NewConnectionPage(selected, page.Size, key), nil
+}
+
+// userIdsPage sorts user ids and returns the page following page.Cursor.
+func userIdsPage(userIds []string, page model.PageRequest) (*model.UserIdPage, error) {
+	after, err := model.DecodeCursor(page.Cursor, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(userIds)
+
+	var selected []string
+	for _, userId := range userIds {
+		if len(selected) > page.Size {
+			break
+		}
+		if userId > after[0] {
+			selected = append(selected, userId)
+		}
+	}
+	return model.NewUserIdPage(selected, page.Size), nil
+}
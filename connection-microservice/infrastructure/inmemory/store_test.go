@@ -0,0 +1,34 @@
+package inmemory
+
+import (
+	"connection-microservice/model"
+	"connection-microservice/model/storetest"
+	"testing"
+)
+
+func TestStores(t *testing.T) {
+	storetest.RunStoreSuite(t, func(t *testing.T) (model.ConnectionStore, model.BlockStore) {
+		graph := NewGraph()
+		return NewConnectionInMemoryStore(graph), NewBlockInMemoryStore(graph)
+	})
+}
+
+func TestImpressionStore(t *testing.T) {
+	storetest.RunImpressionStoreSuite(t, func(t *testing.T) model.ImpressionStore {
+		return NewImpressionInMemoryStore(NewGraph())
+	})
+}
+
+func TestDismissalStore(t *testing.T) {
+	storetest.RunDismissalStoreSuite(t, func(t *testing.T) (model.ConnectionStore, model.DismissalStore) {
+		graph := NewGraph()
+		return NewConnectionInMemoryStore(graph), NewDismissalInMemoryStore(graph)
+	})
+}
+
+func BenchmarkSuggestions(b *testing.B) {
+	storetest.BenchmarkSuggestions(b, func(b *testing.B) (model.ConnectionStore, model.BlockStore) {
+		graph := NewGraph()
+		return NewConnectionInMemoryStore(graph), NewBlockInMemoryStore(graph)
+	}, 100)
+}
@@ -1,6 +1,7 @@
 package persistance
 
 import (
+	"connection-microservice/metrics"
 	"connection-microservice/model"
 	"context"
 	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
@@ -20,6 +21,7 @@ func NewBlockNeo4jStore(driver neo4j.Driver) model.BlockStore {
 func (store *BlockNeo4jStore) BlockUser(ctx context.Context, block model.Block) error {
 	span := tracer.StartSpanFromContext(ctx, "BlockUser")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "BlockUser").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
@@ -51,9 +53,73 @@ func (store *BlockNeo4jStore) BlockUser(ctx context.Context, block model.Block)
 	return nil
 }
 
+func (store *BlockNeo4jStore) BlockAndSever(ctx context.Context, block model.Block) ([]*model.Connection, error) {
+	span := tracer.StartSpanFromContext(ctx, "BlockAndSever")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "BlockAndSever").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	var removed []*model.Connection
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		removed = nil
+		_, err := transaction.Run("MERGE (user:User {userId:$userId}) "+
+			"MERGE (blockedUser:User {userId:$blockedUserId}) "+
+			"MERGE (user)-[b:BLOCK]->(blockedUser)",
+			map[string]interface{}{
+				"userId":        block.UserId,
+				"blockedUserId": block.BlockedUserId,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[c:CONNECT]-(blockedUser {userId:$blockedUserId}) "+
+			"WITH c, startNode(c).userId AS userId, endNode(c).userId AS connectedUserId, c.isConnected AS isConnected, c.pendingConnection AS pendingConnection, "+
+			"c.isMessageNotificationEnabled AS isMessageNotificationEnabled, c.isPostNotificationEnabled AS isPostNotificationEnabled, c.isCommentNotificationEnabled AS isCommentNotificationEnabled, "+
+			"c.createdAt AS createdAt, c.requestedAt AS requestedAt, c.approvedAt AS approvedAt, c.updatedAt AS updatedAt, c.expiredAt AS expiredAt, c.message AS message "+
+			"DELETE c "+
+			"RETURN userId, connectedUserId, isConnected, pendingConnection, isMessageNotificationEnabled, isPostNotificationEnabled, isCommentNotificationEnabled, createdAt, requestedAt, approvedAt, updatedAt, expiredAt, message",
+			map[string]interface{}{
+				"userId":        block.UserId,
+				"blockedUserId": block.BlockedUserId,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			removed = append(removed, &model.Connection{
+				UserId:                       res.Record().Values[0].(string),
+				ConnectedUserId:              res.Record().Values[1].(string),
+				IsConnected:                  res.Record().Values[2].(bool),
+				PendingConnection:            res.Record().Values[3].(bool),
+				IsMessageNotificationEnabled: res.Record().Values[4].(bool),
+				IsPostNotificationEnabled:    res.Record().Values[5].(bool),
+				IsCommentNotificationEnabled: res.Record().Values[6].(bool),
+				CreatedAt:                    toTime(res.Record().Values[7]),
+				RequestedAt:                  toTime(res.Record().Values[8]),
+				ApprovedAt:                   toTime(res.Record().Values[9]),
+				UpdatedAt:                    toTime(res.Record().Values[10]),
+				ExpiredAt:                    toTime(res.Record().Values[11]),
+				Message:                      toString(res.Record().Values[12]),
+			})
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
 func (store *BlockNeo4jStore) UnblockUser(ctx context.Context, block model.Block) error {
 	span := tracer.StartSpanFromContext(ctx, "DeleteConnection")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "UnblockUser").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
@@ -82,6 +148,7 @@ func (store *BlockNeo4jStore) UnblockUser(ctx context.Context, block model.Block
 func (store *BlockNeo4jStore) IsBlocked(ctx context.Context, block model.Block) (bool, error) {
 	span := tracer.StartSpanFromContext(ctx, "IsBlocked")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "IsBlocked").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
@@ -120,6 +187,7 @@ func (store *BlockNeo4jStore) IsBlocked(ctx context.Context, block model.Block)
 func (store *BlockNeo4jStore) GetBlocked(ctx context.Context, userId string) ([]string, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetBlocked")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "GetBlocked").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
@@ -153,6 +221,7 @@ func (store *BlockNeo4jStore) GetBlocked(ctx context.Context, userId string) ([]
 func (store *BlockNeo4jStore) GetBlockedBy(ctx context.Context, userId string) ([]string, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetBlocked")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "GetBlockedBy").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
@@ -182,3 +251,105 @@ func (store *BlockNeo4jStore) GetBlockedBy(ctx context.Context, userId string) (
 	return blockedUserIds, nil
 
 }
+
+func (store *BlockNeo4jStore) GetBlockedPage(ctx context.Context, userId string, page model.PageRequest) (*model.UserIdPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetBlockedPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "GetBlockedPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (user {userId:$userId})-[b:BLOCK]->(blockedUser) " +
+		"WHERE blockedUser.userId > $after " +
+		"RETURN blockedUser.userId ORDER BY blockedUser.userId LIMIT $limit"
+
+	return store.getUserIdPage(ctx, cypher, userId, page)
+}
+
+func (store *BlockNeo4jStore) GetBlockedByPage(ctx context.Context, userId string, page model.PageRequest) (*model.UserIdPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetBlockedByPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "GetBlockedByPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (user)-[b:BLOCK]->(blockedUser {userId:$userId}) " +
+		"WHERE user.userId > $after " +
+		"RETURN user.userId ORDER BY user.userId LIMIT $limit"
+
+	return store.getUserIdPage(ctx, cypher, userId, page)
+}
+
+func (store *BlockNeo4jStore) GetAllBlocksPage(ctx context.Context, page model.PageRequest) (*model.BlockPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetAllBlocksPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("BlockNeo4jStore", "GetAllBlocksPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	after, err := model.DecodeCursor(page.Cursor, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var blocks []model.Block
+	_, err = session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user)-[b:BLOCK]->(blockedUser) "+
+			"WHERE user.userId > $afterUserId OR (user.userId = $afterUserId AND blockedUser.userId > $afterBlockedUserId) "+
+			"RETURN user.userId, blockedUser.userId ORDER BY user.userId, blockedUser.userId LIMIT $limit",
+			map[string]interface{}{
+				"afterUserId":        after[0],
+				"afterBlockedUserId": after[1],
+				"limit":              page.Size + 1,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			blocks = append(blocks, model.Block{
+				UserId:        res.Record().Values[0].(string),
+				BlockedUserId: res.Record().Values[1].(string),
+			})
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return model.NewBlockPage(blocks, page.Size), nil
+}
+
+func (store *BlockNeo4jStore) getUserIdPage(ctx context.Context, cypher string, userId string, page model.PageRequest) (*model.UserIdPage, error) {
+	after, err := model.DecodeCursor(page.Cursor, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var userIds []string
+	_, err = session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run(cypher,
+			map[string]interface{}{
+				"userId": userId,
+				"after":  after[0],
+				"limit":  page.Size + 1,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			userIds = append(userIds, res.Record().Values[0].(string))
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return model.NewUserIdPage(userIds, page.Size), nil
+}
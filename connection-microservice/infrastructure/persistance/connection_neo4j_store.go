@@ -1,12 +1,21 @@
 package persistance
 
 import (
+	"connection-microservice/metrics"
 	"connection-microservice/model"
 	"context"
+	"fmt"
 	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"time"
 )
 
+// suggestionExclusions leaves out of suggestions for user the user itself, users with a connection from the user that has not expired,
+// users blocked by or blocking the user and users the user dismissed. Queries bind user, candidate and $now.
+const suggestionExclusions = "candidate <> user AND none(c IN [(user)-[c:CONNECT]->(candidate) | c] WHERE c.expiredAt IS NULL) " +
+	"AND NOT (user)-[:BLOCK]-(candidate) " +
+	"AND none(d IN [(user)-[d:DISMISSED]->(candidate) | d] WHERE d.expiresAt > $now) "
+
 type ConnectionNeo4jStore struct {
 	driver neo4j.Driver
 }
@@ -20,15 +29,28 @@ func NewConnectionNeo4jStore(driver neo4j.Driver) model.ConnectionStore {
 func (store *ConnectionNeo4jStore) CreateConnection(ctx context.Context, connection *model.Connection) (*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "CreateConnection")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "CreateConnection").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
+	now := time.Now().UTC()
+	connection.CreatedAt = now
+	connection.UpdatedAt = now
+	if connection.PendingConnection {
+		connection.RequestedAt = now
+	}
+	if connection.IsConnected {
+		connection.ApprovedAt = now
+	}
+
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
 	defer session.Close()
 
 	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
 		res, err := transaction.Run("MERGE (user:User {userId:$userId}) "+
 			"MERGE (connectedUser:User {userId:$connectedUserId}) "+
-			"MERGE (user)-[c:CONNECT {isConnected:$isConnected, pendingConnection:$pendingConnection, isMessageNotificationEnabled:$isMessageNotificationEnabled, isPostNotificationEnabled:$isPostNotificationEnabled, isCommentNotificationEnabled:$isCommentNotificationEnabled}]->(connectedUser) RETURN c",
+			"MERGE (user)-[c:CONNECT]->(connectedUser) "+
+			"SET c.isConnected=$isConnected, c.pendingConnection=$pendingConnection, c.isMessageNotificationEnabled=$isMessageNotificationEnabled, c.isPostNotificationEnabled=$isPostNotificationEnabled, c.isCommentNotificationEnabled=$isCommentNotificationEnabled, "+
+			"c.createdAt=$createdAt, c.requestedAt=$requestedAt, c.approvedAt=$approvedAt, c.updatedAt=$updatedAt, c.expiredAt=null, c.message=$message RETURN c",
 			map[string]interface{}{
 				"userId":                       connection.UserId,
 				"connectedUserId":              connection.ConnectedUserId,
@@ -37,6 +59,11 @@ func (store *ConnectionNeo4jStore) CreateConnection(ctx context.Context, connect
 				"isMessageNotificationEnabled": true,
 				"isPostNotificationEnabled":    true,
 				"isCommentNotificationEnabled": true,
+				"createdAt":                    now,
+				"requestedAt":                  timeParam(connection.RequestedAt),
+				"approvedAt":                   timeParam(connection.ApprovedAt),
+				"updatedAt":                    now,
+				"message":                      stringParam(connection.Message),
 			})
 		if err != nil {
 			return nil, err
@@ -60,18 +87,25 @@ func (store *ConnectionNeo4jStore) CreateConnection(ctx context.Context, connect
 }
 
 func (store *ConnectionNeo4jStore) UpdateConnection(ctx context.Context, connection *model.Connection) (*model.Connection, error) {
-	span := tracer.StartSpanFromContext(ctx, "CreateConnection")
+	span := tracer.StartSpanFromContext(ctx, "UpdateConnection")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "UpdateConnection").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
+	connection.UpdatedAt = time.Now().UTC()
+
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
 	defer session.Close()
 
-	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+	matched, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
 		res, err := transaction.Run("MATCH (user {userId:$userId})-[c:CONNECT]->(connectedUser {userId:$connectedUserId}) "+
-			"SET c.isConnected=$isConnected, c.pendingConnection=$pendingConnection , c.isMessageNotificationEnabled=$isMessageNotificationEnabled , c.isPostNotificationEnabled=$isPostNotificationEnabled , c.isCommentNotificationEnabled=$isCommentNotificationEnabled "+
+			"SET c.isConnected=$isConnected, c.pendingConnection=$pendingConnection , c.isMessageNotificationEnabled=$isMessageNotificationEnabled , c.isPostNotificationEnabled=$isPostNotificationEnabled , c.isCommentNotificationEnabled=$isCommentNotificationEnabled , "+
+			"c.approvedAt=coalesce($approvedAt, c.approvedAt) , c.updatedAt=$updatedAt , c.message=$message "+
 			"RETURN c",
 			map[string]interface{}{
+				"approvedAt":                   timeParam(connection.ApprovedAt),
+				"updatedAt":                    connection.UpdatedAt,
+				"message":                      stringParam(connection.Message),
 				"userId":                       connection.UserId,
 				"connectedUserId":              connection.ConnectedUserId,
 				"isConnected":                  connection.IsConnected,
@@ -81,63 +115,104 @@ func (store *ConnectionNeo4jStore) UpdateConnection(ctx context.Context, connect
 				"isCommentNotificationEnabled": connection.IsCommentNotificationEnabled,
 			})
 		if err != nil {
-			return nil, err
+			return false, err
 		}
 
 		if res.Next() {
-			return res.Record().Values[0], nil
+			return true, nil
 		}
-		return nil, res.Err()
-
+		return false, res.Err()
 	})
 
 	if err != nil {
 		return nil, err
 	}
-
+	if !matched.(bool) {
+		return nil, model.ErrConnectionNotFound
+	}
 	return connection, nil
 }
 
 func (store *ConnectionNeo4jStore) DeleteConnection(ctx context.Context, userId string, connectedUserId string) error {
 	span := tracer.StartSpanFromContext(ctx, "DeleteConnection")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "DeleteConnection").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
 	defer session.Close()
 
-	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
-		_, err := transaction.Run("MATCH (user {userId:$userId})-[c:CONNECT]->(connectedUser {userId:$connectedUserId}) "+
-			"DELETE c",
+	deleted, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[c:CONNECT]->(connectedUser {userId:$connectedUserId}) "+
+			"DELETE c RETURN count(c)",
 			map[string]interface{}{
 				"userId":          userId,
 				"connectedUserId": connectedUserId,
 			})
 		if err != nil {
-			return nil, err
+			return int64(0), err
 		}
 
-		return nil, nil
+		if res.Next() {
+			return res.Record().Values[0], nil
+		}
+		return int64(0), res.Err()
 	})
 
 	if err != nil {
 		return err
 	}
+	if deleted.(int64) == 0 {
+		return model.ErrConnectionNotFound
+	}
 	return nil
 }
 
+func (store *ConnectionNeo4jStore) DeletePendingConnection(ctx context.Context, userId string, connectedUserId string) (bool, error) {
+	span := tracer.StartSpanFromContext(ctx, "DeletePendingConnection")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "DeletePendingConnection").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	deleted, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[c:CONNECT {isConnected:false, pendingConnection:true}]->(connectedUser {userId:$connectedUserId}) "+
+			"DELETE c RETURN count(c)",
+			map[string]interface{}{
+				"userId":          userId,
+				"connectedUserId": connectedUserId,
+			})
+		if err != nil {
+			return int64(0), err
+		}
+
+		if res.Next() {
+			return res.Record().Values[0], nil
+		}
+		return int64(0), res.Err()
+	})
+
+	if err != nil {
+		return false, err
+	}
+	return deleted.(int64) > 0, nil
+}
+
 func (store *ConnectionNeo4jStore) GetConnectionByUsersId(ctx context.Context, userId string, connectedUserId string) (*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetConnectionByUsersId")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetConnectionByUsersId").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
 	defer session.Close()
 
-	var connection = model.Connection{}
+	var connection *model.Connection
 	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
 		res, err := transaction.Run("MATCH (user {userId:$userId})-[c:CONNECT]->(connectedUser {userId:$connectedUserId}) "+
-			"RETURN c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled",
+			"RETURN c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message",
 			map[string]interface{}{
 				"userId":          userId,
 				"connectedUserId": connectedUserId,
@@ -147,7 +222,7 @@ func (store *ConnectionNeo4jStore) GetConnectionByUsersId(ctx context.Context, u
 		}
 
 		if res.Next() {
-			connection = model.Connection{
+			connection = &model.Connection{
 				UserId:                       userId,
 				ConnectedUserId:              connectedUserId,
 				IsConnected:                  res.Record().Values[0].(bool),
@@ -155,26 +230,35 @@ func (store *ConnectionNeo4jStore) GetConnectionByUsersId(ctx context.Context, u
 				IsMessageNotificationEnabled: res.Record().Values[2].(bool),
 				IsPostNotificationEnabled:    res.Record().Values[3].(bool),
 				IsCommentNotificationEnabled: res.Record().Values[4].(bool),
+				CreatedAt:                    toTime(res.Record().Values[5]),
+				RequestedAt:                  toTime(res.Record().Values[6]),
+				ApprovedAt:                   toTime(res.Record().Values[7]),
+				UpdatedAt:                    toTime(res.Record().Values[8]),
+				ExpiredAt:                    toTime(res.Record().Values[9]),
+				Message:                      toString(res.Record().Values[10]),
 			}
 			return nil, nil
 		}
 		return nil, res.Err()
-
 	})
 
 	if err != nil {
 		return nil, err
 	}
-	return &connection, nil
+	if connection == nil {
+		return nil, model.ErrConnectionNotFound
+	}
+	return connection, nil
 }
 
 func (store *ConnectionNeo4jStore) GetAllConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetConnectionsByUserId")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetAllConnectionsByUserId").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	cypher := "MATCH (user {userId:$userId})-[c:CONNECT]->(connectedUser) " +
-		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled"
+	cypher := "MATCH (user {userId:$userId})-[c:CONNECT]->(connectedUser) WHERE c.expiredAt IS NULL " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message"
 
 	params := map[string]interface{}{
 		"userId": userId,
@@ -186,8 +270,8 @@ func (store *ConnectionNeo4jStore) GetAllConnectionsByUserId(ctx context.Context
 		return nil, err
 	}
 
-	cypher = "MATCH (user)-[c:CONNECT]->(connectedUser {userId:$userId}) " +
-		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled"
+	cypher = "MATCH (user)-[c:CONNECT]->(connectedUser {userId:$userId}) WHERE c.expiredAt IS NULL " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message"
 
 	params = map[string]interface{}{
 		"userId": userId,
@@ -207,10 +291,11 @@ func (store *ConnectionNeo4jStore) GetAllConnectionsByUserId(ctx context.Context
 func (store *ConnectionNeo4jStore) GetFollowings(ctx context.Context, userId string) ([]*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetFollowings")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetFollowings").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	cypher := "MATCH (user {userId:$userId})-[c:CONNECT {isConnected:true}]->(connectedUser) " +
-		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled"
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message"
 
 	params := map[string]interface{}{
 		"userId": userId,
@@ -228,10 +313,11 @@ func (store *ConnectionNeo4jStore) GetFollowings(ctx context.Context, userId str
 func (store *ConnectionNeo4jStore) GetFollowers(ctx context.Context, connectedUserId string) ([]*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetFollowers")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetFollowers").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	cypher := "MATCH (user)-[c:CONNECT {isConnected:true}]->(connectedUser {userId:$connectedUserId}) " +
-		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled"
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message"
 
 	params := map[string]interface{}{
 		"connectedUserId": connectedUserId,
@@ -249,10 +335,11 @@ func (store *ConnectionNeo4jStore) GetFollowers(ctx context.Context, connectedUs
 func (store *ConnectionNeo4jStore) GetAllRequestConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetAllRequestConnectionsByUserId")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetAllRequestConnectionsByUserId").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	cypher := "MATCH (user)-[c:CONNECT {isConnected:false, pendingConnection:true}]->(connectedUser {userId:$connectedUserId}) " +
-		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled"
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message"
 
 	params := map[string]interface{}{
 		"connectedUserId": userId,
@@ -270,10 +357,11 @@ func (store *ConnectionNeo4jStore) GetAllRequestConnectionsByUserId(ctx context.
 func (store *ConnectionNeo4jStore) GetAllPendingConnectionsByUserId(ctx context.Context, userId string) ([]*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetAllPendingConnectionsByUserId")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetAllPendingConnectionsByUserId").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	cypher := "MATCH (user {userId:$userId})-[c:CONNECT {isConnected:false, pendingConnection:true}]->(connectedUser) " +
-		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled"
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message"
 
 	params := map[string]interface{}{
 		"userId": userId,
@@ -288,6 +376,261 @@ func (store *ConnectionNeo4jStore) GetAllPendingConnectionsByUserId(ctx context.
 	return connections, nil
 }
 
+func (store *ConnectionNeo4jStore) GetAllConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetAllConnectionsPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetAllConnectionsPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	after, err := model.DecodeCursor(page.Cursor, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	cypher := "CALL { " +
+		"MATCH (user {userId:$userId})-[c:CONNECT]->(connectedUser) RETURN user, c, connectedUser " +
+		"UNION " +
+		"MATCH (user)-[c:CONNECT]->(connectedUser {userId:$userId}) RETURN user, c, connectedUser } " +
+		"WITH user, c, connectedUser " +
+		"WHERE c.expiredAt IS NULL AND (user.userId > $afterUserId OR (user.userId = $afterUserId AND connectedUser.userId > $afterConnectedUserId)) " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message " +
+		"ORDER BY user.userId, connectedUser.userId LIMIT $limit"
+
+	params := map[string]interface{}{
+		"userId":               userId,
+		"afterUserId":          after[0],
+		"afterConnectedUserId": after[1],
+		"limit":                page.Size + 1,
+	}
+
+	connections, err := store.GetConnections(ctx, cypher, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewConnectionPage(connections, page.Size, pairKey), nil
+}
+
+func (store *ConnectionNeo4jStore) GetFollowingsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetFollowingsPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetFollowingsPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (user {userId:$userId})-[c:CONNECT {isConnected:true}]->(connectedUser) " +
+		"WHERE connectedUser.userId > $after " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message " +
+		"ORDER BY connectedUser.userId LIMIT $limit"
+
+	params := map[string]interface{}{
+		"userId": userId,
+	}
+
+	return store.getConnectionsPage(ctx, cypher, params, page, connectedUserKey)
+}
+
+func (store *ConnectionNeo4jStore) GetFollowersPage(ctx context.Context, connectedUserId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetFollowersPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetFollowersPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (user)-[c:CONNECT {isConnected:true}]->(connectedUser {userId:$connectedUserId}) " +
+		"WHERE user.userId > $after " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message " +
+		"ORDER BY user.userId LIMIT $limit"
+
+	params := map[string]interface{}{
+		"connectedUserId": connectedUserId,
+	}
+
+	return store.getConnectionsPage(ctx, cypher, params, page, userKey)
+}
+
+func (store *ConnectionNeo4jStore) GetAllRequestConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetAllRequestConnectionsPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetAllRequestConnectionsPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (user)-[c:CONNECT {isConnected:false, pendingConnection:true}]->(connectedUser {userId:$connectedUserId}) " +
+		"WHERE user.userId > $after " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message " +
+		"ORDER BY user.userId LIMIT $limit"
+
+	params := map[string]interface{}{
+		"connectedUserId": userId,
+	}
+
+	return store.getConnectionsPage(ctx, cypher, params, page, userKey)
+}
+
+func (store *ConnectionNeo4jStore) GetAllPendingConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetAllPendingConnectionsPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetAllPendingConnectionsPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (user {userId:$userId})-[c:CONNECT {isConnected:false, pendingConnection:true}]->(connectedUser) " +
+		"WHERE connectedUser.userId > $after " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message " +
+		"ORDER BY connectedUser.userId LIMIT $limit"
+
+	params := map[string]interface{}{
+		"userId": userId,
+	}
+
+	return store.getConnectionsPage(ctx, cypher, params, page, connectedUserKey)
+}
+
+func (store *ConnectionNeo4jStore) GetAllExpiredConnectionsPage(ctx context.Context, userId string, page model.PageRequest) (*model.ConnectionPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetAllExpiredConnectionsPage")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetAllExpiredConnectionsPage").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (user {userId:$userId})-[c:CONNECT {isConnected:false, pendingConnection:false}]->(connectedUser) " +
+		"WHERE c.expiredAt IS NOT NULL AND connectedUser.userId > $after " +
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message " +
+		"ORDER BY connectedUser.userId LIMIT $limit"
+
+	params := map[string]interface{}{
+		"userId": userId,
+	}
+
+	return store.getConnectionsPage(ctx, cypher, params, page, connectedUserKey)
+}
+
+func (store *ConnectionNeo4jStore) ExpirePendingConnections(ctx context.Context, requestedBefore time.Time) ([]*model.Connection, error) {
+	span := tracer.StartSpanFromContext(ctx, "ExpirePendingConnections")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "ExpirePendingConnections").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	var expired []*model.Connection
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		expired = nil
+		res, err := transaction.Run("MATCH (user)-[c:CONNECT {isConnected:false, pendingConnection:true}]->(connectedUser) "+
+			"WHERE c.requestedAt < $requestedBefore "+
+			"SET c.pendingConnection=false, c.expiredAt=$now, c.updatedAt=$now "+
+			"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message",
+			map[string]interface{}{
+				"requestedBefore": requestedBefore,
+				"now":             time.Now().UTC(),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			expired = append(expired, toConnection(res.Record().Values))
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+func (store *ConnectionNeo4jStore) CountPendingConnections(ctx context.Context, requestedBefore time.Time) (int64, error) {
+	span := tracer.StartSpanFromContext(ctx, "CountPendingConnections")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "CountPendingConnections").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	pending, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH ()-[c:CONNECT {isConnected:false, pendingConnection:true}]->() "+
+			"WHERE c.requestedAt < $requestedBefore "+
+			"RETURN count(c)",
+			map[string]interface{}{
+				"requestedBefore": requestedBefore,
+			})
+		if err != nil {
+			return int64(0), err
+		}
+
+		if res.Next() {
+			return res.Record().Values[0], nil
+		}
+		return int64(0), res.Err()
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return pending.(int64), nil
+}
+
+func (store *ConnectionNeo4jStore) DeleteUserEdges(ctx context.Context, userId string) (int64, error) {
+	span := tracer.StartSpanFromContext(ctx, "DeleteUserEdges")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "DeleteUserEdges").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	deleted, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user:User {userId:$userId})-[r]-() "+
+			"DELETE r RETURN count(r)",
+			map[string]interface{}{
+				"userId": userId,
+			})
+		if err != nil {
+			return int64(0), err
+		}
+
+		if res.Next() {
+			return res.Record().Values[0], nil
+		}
+		return int64(0), res.Err()
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return deleted.(int64), nil
+}
+
+// getConnectionsPage runs a query ordered by a single user id that filters on $after and is limited by $limit.
+func (store *ConnectionNeo4jStore) getConnectionsPage(ctx context.Context, cypher string, params map[string]interface{}, page model.PageRequest, key func(connection *model.Connection) []string) (*model.ConnectionPage, error) {
+	after, err := model.DecodeCursor(page.Cursor, 1)
+	if err != nil {
+		return nil, err
+	}
+	params["after"] = after[0]
+	params["limit"] = page.Size + 1
+
+	connections, err := store.GetConnections(ctx, cypher, params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewConnectionPage(connections, page.Size, key), nil
+}
+
+func userKey(connection *model.Connection) []string {
+	return []string{connection.UserId}
+}
+
+func connectedUserKey(connection *model.Connection) []string {
+	return []string{connection.ConnectedUserId}
+}
+
+func pairKey(connection *model.Connection) []string {
+	return []string{connection.UserId, connection.ConnectedUserId}
+}
+
 func (store *ConnectionNeo4jStore) GetConnections(ctx context.Context, cypher string, params map[string]interface{}) ([]*model.Connection, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetConnections")
 	defer span.Finish()
@@ -304,15 +647,7 @@ func (store *ConnectionNeo4jStore) GetConnections(ctx context.Context, cypher st
 		}
 
 		for res.Next() {
-			connection = append(connection, &model.Connection{
-				UserId:                       res.Record().Values[0].(string),
-				ConnectedUserId:              res.Record().Values[1].(string),
-				IsConnected:                  res.Record().Values[2].(bool),
-				PendingConnection:            res.Record().Values[3].(bool),
-				IsMessageNotificationEnabled: res.Record().Values[4].(bool),
-				IsPostNotificationEnabled:    res.Record().Values[5].(bool),
-				IsCommentNotificationEnabled: res.Record().Values[6].(bool),
-			})
+			connection = append(connection, toConnection(res.Record().Values))
 		}
 		return nil, res.Err()
 
@@ -324,13 +659,133 @@ func (store *ConnectionNeo4jStore) GetConnections(ctx context.Context, cypher st
 	return connection, nil
 }
 
+// toConnection maps a row returned in the column order of the connection queries.
+func toConnection(values []interface{}) *model.Connection {
+	return &model.Connection{
+		UserId:                       values[0].(string),
+		ConnectedUserId:              values[1].(string),
+		IsConnected:                  values[2].(bool),
+		PendingConnection:            values[3].(bool),
+		IsMessageNotificationEnabled: values[4].(bool),
+		IsPostNotificationEnabled:    values[5].(bool),
+		IsCommentNotificationEnabled: values[6].(bool),
+		CreatedAt:                    toTime(values[7]),
+		RequestedAt:                  toTime(values[8]),
+		ApprovedAt:                   toTime(values[9]),
+		UpdatedAt:                    toTime(values[10]),
+		ExpiredAt:                    toTime(values[11]),
+		Message:                      toString(values[12]),
+	}
+}
+
+func (store *ConnectionNeo4jStore) GetMutualConnections(ctx context.Context, userId string, otherUserId string, excludedUserIds []string, sampleSize int) (*model.MutualConnections, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetMutualConnections")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetMutualConnections").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	// a null list would make the IN filter drop every row
+	if excludedUserIds == nil {
+		excludedUserIds = []string{}
+	}
+
+	mutual := &model.MutualConnections{UserIds: []string{}}
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[:CONNECT {isConnected:true}]->(mutual)<-[:CONNECT {isConnected:true}]-(other {userId:$otherUserId}) "+
+			"WHERE NOT mutual.userId IN $excludedUserIds "+
+			"WITH mutual.userId AS mutualId ORDER BY mutualId "+
+			"RETURN count(mutualId), collect(mutualId)[0..$sampleSize]",
+			map[string]interface{}{
+				"userId":          userId,
+				"otherUserId":     otherUserId,
+				"excludedUserIds": excludedUserIds,
+				"sampleSize":      sampleSize,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		if res.Next() {
+			mutual.Count = res.Record().Values[0].(int64)
+			for _, id := range res.Record().Values[1].([]interface{}) {
+				mutual.UserIds = append(mutual.UserIds, id.(string))
+			}
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return mutual, nil
+}
+
+func (store *ConnectionNeo4jStore) GetDegrees(ctx context.Context, userId string, targetUserIds []string, excludedUserIds []string, maxDepth int, withPath bool) ([]*model.Degree, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetDegrees")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetDegrees").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	if excludedUserIds == nil {
+		excludedUserIds = []string{}
+	}
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	// the upper bound of a variable length pattern can not be a parameter
+	cypher := fmt.Sprintf("MATCH (user {userId:$userId}) "+
+		"UNWIND $targetUserIds AS targetUserId "+
+		"MATCH (target {userId:targetUserId}) WHERE target.userId <> $userId "+
+		"OPTIONAL MATCH path = shortestPath((user)-[:CONNECT*..%d]->(target)) "+
+		"WHERE all(c IN relationships(path) WHERE c.isConnected = true) "+
+		"AND none(n IN nodes(path)[1..-1] WHERE n.userId IN $excludedUserIds) "+
+		"RETURN targetUserId, length(path), [n IN nodes(path) | n.userId]", maxDepth)
+
+	var degrees []*model.Degree
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run(cypher,
+			map[string]interface{}{
+				"userId":          userId,
+				"targetUserIds":   targetUserIds,
+				"excludedUserIds": excludedUserIds,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			degree := &model.Degree{UserId: res.Record().Values[0].(string)}
+			if length, ok := res.Record().Values[1].(int64); ok {
+				degree.Degree = int(length)
+				if withPath {
+					for _, id := range res.Record().Values[2].([]interface{}) {
+						degree.Path = append(degree.Path, id.(string))
+					}
+				}
+			}
+			degrees = append(degrees, degree)
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return degrees, nil
+}
+
 func (store *ConnectionNeo4jStore) GetFollowingsOfMyFollowings(ctx context.Context, connectedUserId string, userId string) ([]string, error) {
 	span := tracer.StartSpanFromContext(ctx, "GetAllRequestConnectionsByUserId")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetFollowingsOfMyFollowings").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
 	cypher := "MATCH (user {userId:$connectedUserId})-[c:CONNECT {isConnected:true}]->(connectedUser) WHERE NOT ({userId:$userId})-[:CONNECT {isConnected:true}]->(connectedUser)" +
-		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled LIMIT 10"
+		"RETURN user.userId, connectedUser.userId, c.isConnected, c.pendingConnection, c.isMessageNotificationEnabled, c.isPostNotificationEnabled, c.isCommentNotificationEnabled, c.createdAt, c.requestedAt, c.approvedAt, c.updatedAt, c.expiredAt, c.message LIMIT 10"
 
 	params := map[string]interface{}{
 		"connectedUserId": connectedUserId,
@@ -352,30 +807,190 @@ func (store *ConnectionNeo4jStore) GetFollowingsOfMyFollowings(ctx context.Conte
 	return retVal, nil
 }
 
-func (store *ConnectionNeo4jStore) GetRandom(ctx context.Context, userId string, limit int) ([]string, error) {
-	span := tracer.StartSpanFromContext(ctx, "GetRandom")
+func (store *ConnectionNeo4jStore) GetSuggestionCandidates(ctx context.Context, userId string, limit int) ([]*model.SuggestionCandidate, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetSuggestionCandidates")
 	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetSuggestionCandidates").ObserveDuration()
 	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	cypher := "MATCH (user) WHERE NOT (user.userId=$userId OR({userId:$userId})-[:CONNECT {isConnected:true}]->(user))" +
-		"RETURN user.userId, user.userId as f, false as a, false as b, false as c, false as d, false as e LIMIT $limit"
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
 
-	params := map[string]interface{}{
-		"userId": userId,
-		"limit":  limit,
+	var candidates []*model.SuggestionCandidate
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[:CONNECT {isConnected:true}]->(following)-[:CONNECT {isConnected:true}]->(candidate) "+
+			"WHERE "+suggestionExclusions+
+			"WITH DISTINCT candidate, following "+
+			"WITH candidate, following, size([(following)-[:CONNECT {isConnected:true}]->() | 1]) AS followingDegree "+
+			"WITH candidate, count(following) AS mutualCount, sum(1.0 / log(1 + followingDegree)) AS adamicAdar "+
+			"ORDER BY mutualCount DESC, candidate.userId LIMIT $limit "+
+			"RETURN candidate.userId, mutualCount, size([()-[:CONNECT {isConnected:true}]->(candidate) | 1]), adamicAdar",
+			map[string]interface{}{
+				"userId": userId,
+				"limit":  limit,
+				"now":    time.Now().UTC(),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			candidates = append(candidates, &model.SuggestionCandidate{
+				UserId:        res.Record().Values[0].(string),
+				MutualCount:   int(res.Record().Values[1].(int64)),
+				FollowerCount: int(res.Record().Values[2].(int64)),
+				AdamicAdar:    res.Record().Values[3].(float64),
+			})
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
 	}
+	return candidates, nil
+}
 
-	connections, err := store.GetConnections(ctx, cypher, params)
+func (store *ConnectionNeo4jStore) GetPopularUsers(ctx context.Context, userId string, limit int) ([]*model.SuggestionCandidate, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetPopularUsers")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetPopularUsers").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var candidates []*model.SuggestionCandidate
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId}), (candidate:User) "+
+			"WHERE "+suggestionExclusions+
+			"WITH candidate, size([()-[:CONNECT {isConnected:true}]->(candidate) | 1]) AS followerCount "+
+			"RETURN candidate.userId, followerCount ORDER BY followerCount DESC, candidate.userId LIMIT $limit",
+			map[string]interface{}{
+				"userId": userId,
+				"limit":  limit,
+				"now":    time.Now().UTC(),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			candidates = append(candidates, &model.SuggestionCandidate{
+				UserId:        res.Record().Values[0].(string),
+				FollowerCount: int(res.Record().Values[1].(int64)),
+			})
+		}
+		return nil, res.Err()
+	})
 
 	if err != nil {
 		return nil, err
 	}
+	return candidates, nil
+}
 
-	var retVal []string
+func (store *ConnectionNeo4jStore) GetMostFollowedUsers(ctx context.Context, limit int) ([]*model.SuggestionCandidate, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetMostFollowedUsers")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetMostFollowedUsers").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
 
-	for _, connection := range connections {
-		retVal = append(retVal, connection.ConnectedUserId)
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var candidates []*model.SuggestionCandidate
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (candidate:User) "+
+			"WITH candidate, size([()-[:CONNECT {isConnected:true}]->(candidate) | 1]) AS followerCount "+
+			"RETURN candidate.userId, followerCount ORDER BY followerCount DESC, candidate.userId LIMIT $limit",
+			map[string]interface{}{
+				"limit": limit,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			candidates = append(candidates, &model.SuggestionCandidate{
+				UserId:        res.Record().Values[0].(string),
+				FollowerCount: int(res.Record().Values[1].(int64)),
+			})
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
 	}
+	return candidates, nil
+}
 
-	return retVal, nil
+func (store *ConnectionNeo4jStore) GetRecentlyActiveUsers(ctx context.Context, limit int) ([]string, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetRecentlyActiveUsers")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetRecentlyActiveUsers").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	cypher := "MATCH (candidate:User)-[c:CONNECT]->() " +
+		"WITH candidate, max(c.updatedAt) AS lastActiveAt " +
+		"RETURN candidate.userId ORDER BY lastActiveAt DESC, candidate.userId LIMIT $limit"
+
+	return store.getUserIds(ctx, cypher, map[string]interface{}{
+		"limit": limit,
+	})
+}
+
+// GetRandomUsers fetches every user and samples them with model.SampleUserIds, since Cypher has no seeded shuffle.
+func (store *ConnectionNeo4jStore) GetRandomUsers(ctx context.Context, limit int, seed int64) ([]string, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetRandomUsers")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetRandomUsers").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	userIds, err := store.getUserIds(ctx, "MATCH (candidate:User) RETURN candidate.userId", nil)
+	if err != nil {
+		return nil, err
+	}
+	return model.SampleUserIds(userIds, limit, seed), nil
+}
+
+func (store *ConnectionNeo4jStore) GetSuggestionExclusions(ctx context.Context, userId string) ([]string, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetSuggestionExclusions")
+	defer span.Finish()
+	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "GetSuggestionExclusions").ObserveDuration()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	// every excluded user shares a relationship with the user, so only neighbours need checking
+	cypher := "MATCH (user {userId:$userId})--(candidate:User) " +
+		"WITH DISTINCT user, candidate WHERE NOT (" + suggestionExclusions + ") " +
+		"RETURN candidate.userId"
+
+	return store.getUserIds(ctx, cypher, map[string]interface{}{
+		"userId": userId,
+		"now":    time.Now().UTC(),
+	})
+}
+
+func (store *ConnectionNeo4jStore) getUserIds(ctx context.Context, cypher string, params map[string]interface{}) ([]string, error) {
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var userIds []string
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run(cypher, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			userIds = append(userIds, res.Record().Values[0].(string))
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return userIds, nil
 }
@@ -0,0 +1,182 @@
+package persistance
+
+import (
+	"connection-microservice/model"
+	"context"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"time"
+)
+
+type DismissalNeo4jStore struct {
+	driver neo4j.Driver
+}
+
+func NewDismissalNeo4jStore(driver neo4j.Driver) model.DismissalStore {
+	return &DismissalNeo4jStore{
+		driver: driver,
+	}
+}
+
+func (store *DismissalNeo4jStore) DismissSuggestion(ctx context.Context, dismissal model.Dismissal) error {
+	span := tracer.StartSpanFromContext(ctx, "DismissSuggestion")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		_, err := transaction.Run("MERGE (user:User {userId:$userId}) "+
+			"MERGE (dismissedUser:User {userId:$dismissedUserId}) "+
+			"MERGE (user)-[d:DISMISSED]->(dismissedUser) "+
+			"SET d.dismissedAt=$dismissedAt, d.expiresAt=$expiresAt",
+			map[string]interface{}{
+				"userId":          dismissal.UserId,
+				"dismissedUserId": dismissal.DismissedUserId,
+				"dismissedAt":     dismissal.DismissedAt,
+				"expiresAt":       dismissal.ExpiresAt,
+			})
+		return nil, err
+	})
+	return err
+}
+
+func (store *DismissalNeo4jStore) UndoDismissal(ctx context.Context, userId string, dismissedUserId string) (bool, error) {
+	span := tracer.StartSpanFromContext(ctx, "UndoDismissal")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	undone, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[d:DISMISSED]->(dismissedUser {userId:$dismissedUserId}) "+
+			"WITH d, d.expiresAt > $now AS active DELETE d RETURN active",
+			map[string]interface{}{
+				"userId":          userId,
+				"dismissedUserId": dismissedUserId,
+				"now":             time.Now().UTC(),
+			})
+		if err != nil {
+			return false, err
+		}
+
+		if res.Next() {
+			return res.Record().Values[0], nil
+		}
+		return false, res.Err()
+	})
+
+	if err != nil {
+		return false, err
+	}
+	return undone.(bool), nil
+}
+
+func (store *DismissalNeo4jStore) GetDismissalsPage(ctx context.Context, userId string, page model.PageRequest) (*model.DismissalPage, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetDismissalsPage")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	after, err := model.DecodeCursor(page.Cursor, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var dismissals []*model.Dismissal
+	_, err = session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[d:DISMISSED]->(dismissedUser) "+
+			"WHERE d.expiresAt > $now AND dismissedUser.userId > $after "+
+			"RETURN user.userId, dismissedUser.userId, d.dismissedAt, d.expiresAt ORDER BY dismissedUser.userId LIMIT $limit",
+			map[string]interface{}{
+				"userId": userId,
+				"now":    time.Now().UTC(),
+				"after":  after[0],
+				"limit":  page.Size + 1,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			dismissals = append(dismissals, &model.Dismissal{
+				UserId:          res.Record().Values[0].(string),
+				DismissedUserId: res.Record().Values[1].(string),
+				DismissedAt:     toTime(res.Record().Values[2]),
+				ExpiresAt:       toTime(res.Record().Values[3]),
+			})
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return model.NewDismissalPage(dismissals, page.Size), nil
+}
+
+func (store *DismissalNeo4jStore) GetDismissedUserIds(ctx context.Context, userId string) ([]string, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetDismissedUserIds")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var userIds []string
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[d:DISMISSED]->(dismissedUser) "+
+			"WHERE d.expiresAt > $now RETURN dismissedUser.userId",
+			map[string]interface{}{
+				"userId": userId,
+				"now":    time.Now().UTC(),
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			userIds = append(userIds, res.Record().Values[0].(string))
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
+
+func (store *DismissalNeo4jStore) DeleteExpiredDismissals(ctx context.Context, now time.Time) (int64, error) {
+	span := tracer.StartSpanFromContext(ctx, "DeleteExpiredDismissals")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	deleted, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH ()-[d:DISMISSED]->() WHERE d.expiresAt <= $now "+
+			"DELETE d RETURN count(d)",
+			map[string]interface{}{
+				"now": now,
+			})
+		if err != nil {
+			return int64(0), err
+		}
+
+		if res.Next() {
+			return res.Record().Values[0], nil
+		}
+		return int64(0), res.Err()
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return deleted.(int64), nil
+}
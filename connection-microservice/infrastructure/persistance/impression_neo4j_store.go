@@ -0,0 +1,133 @@
+package persistance
+
+import (
+	"connection-microservice/model"
+	"context"
+	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"time"
+)
+
+type ImpressionNeo4jStore struct {
+	driver neo4j.Driver
+}
+
+func NewImpressionNeo4jStore(driver neo4j.Driver) model.ImpressionStore {
+	return &ImpressionNeo4jStore{
+		driver: driver,
+	}
+}
+
+func (store *ImpressionNeo4jStore) RecordImpressions(ctx context.Context, impressions []*model.SuggestionImpression) error {
+	span := tracer.StartSpanFromContext(ctx, "RecordImpressions")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	if len(impressions) == 0 {
+		return nil
+	}
+
+	var rows []map[string]interface{}
+	for _, impression := range impressions {
+		rows = append(rows, map[string]interface{}{
+			"userId":          impression.UserId,
+			"suggestedUserId": impression.SuggestedUserId,
+			"experiment":      impression.Experiment,
+			"variant":         impression.Variant,
+			"shownAt":         impression.LastShownAt,
+		})
+	}
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	_, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		_, err := transaction.Run("UNWIND $impressions AS impression "+
+			"MERGE (user:User {userId:impression.userId}) "+
+			"MERGE (suggestedUser:User {userId:impression.suggestedUserId}) "+
+			"MERGE (user)-[s:SUGGESTED {experiment:impression.experiment, variant:impression.variant}]->(suggestedUser) "+
+			"ON CREATE SET s.count=0 "+
+			"SET s.count=s.count+1, s.lastShownAt=impression.shownAt",
+			map[string]interface{}{
+				"impressions": rows,
+			})
+		return nil, err
+	})
+	return err
+}
+
+func (store *ImpressionNeo4jStore) AcceptImpression(ctx context.Context, userId string, suggestedUserId string, shownAfter time.Time, acceptedAt time.Time) (*model.SuggestionImpression, error) {
+	span := tracer.StartSpanFromContext(ctx, "AcceptImpression")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	impression, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH (user {userId:$userId})-[s:SUGGESTED]->(suggestedUser {userId:$suggestedUserId}) "+
+			"WHERE s.acceptedAt IS NULL AND s.lastShownAt >= $shownAfter "+
+			"WITH user, suggestedUser, s ORDER BY s.lastShownAt DESC LIMIT 1 "+
+			"SET s.acceptedAt=$acceptedAt "+
+			"RETURN user.userId, suggestedUser.userId, s.experiment, s.variant, s.count, s.lastShownAt, s.acceptedAt",
+			map[string]interface{}{
+				"userId":          userId,
+				"suggestedUserId": suggestedUserId,
+				"shownAfter":      shownAfter,
+				"acceptedAt":      acceptedAt,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		if res.Next() {
+			return &model.SuggestionImpression{
+				UserId:          res.Record().Values[0].(string),
+				SuggestedUserId: res.Record().Values[1].(string),
+				Experiment:      res.Record().Values[2].(string),
+				Variant:         res.Record().Values[3].(string),
+				Count:           res.Record().Values[4].(int64),
+				LastShownAt:     toTime(res.Record().Values[5]),
+				AcceptedAt:      toTime(res.Record().Values[6]),
+			}, nil
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil || impression == nil {
+		return nil, err
+	}
+	return impression.(*model.SuggestionImpression), nil
+}
+
+func (store *ImpressionNeo4jStore) GetVariantStats(ctx context.Context, experiment string) ([]*model.VariantStats, error) {
+	span := tracer.StartSpanFromContext(ctx, "GetVariantStats")
+	defer span.Finish()
+	ctx = tracer.ContextWithSpan(context.Background(), span)
+
+	session := store.driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+
+	var stats []*model.VariantStats
+	_, err := session.ReadTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH ()-[s:SUGGESTED {experiment:$experiment}]->() "+
+			"RETURN s.variant, sum(s.count), count(s), count(s.acceptedAt) ORDER BY s.variant",
+			map[string]interface{}{
+				"experiment": experiment,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		for res.Next() {
+			stats = append(stats, model.NewVariantStats(experiment, res.Record().Values[0].(string),
+				res.Record().Values[1].(int64), res.Record().Values[2].(int64), res.Record().Values[3].(int64)))
+		}
+		return nil, res.Err()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,37 @@
+package persistance
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"time"
+)
+
+// BackfillConnectionTimestamps sets lifecycle timestamps on CONNECT edges created before they were tracked.
+// The real times are unknown, so the time of the backfill is used. It returns the number of updated edges.
+func BackfillConnectionTimestamps(driver neo4j.Driver) (int64, error) {
+	session := driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	updated, err := session.WriteTransaction(func(transaction neo4j.Transaction) (interface{}, error) {
+		res, err := transaction.Run("MATCH ()-[c:CONNECT]->() WHERE c.createdAt IS NULL "+
+			"SET c.createdAt=$now, c.updatedAt=coalesce(c.updatedAt, $now), "+
+			"c.requestedAt=CASE WHEN c.pendingConnection THEN $now ELSE c.requestedAt END, "+
+			"c.approvedAt=CASE WHEN c.isConnected THEN $now ELSE c.approvedAt END "+
+			"RETURN count(c)",
+			map[string]interface{}{
+				"now": time.Now().UTC(),
+			})
+		if err != nil {
+			return int64(0), err
+		}
+
+		if res.Next() {
+			return res.Record().Values[0], nil
+		}
+		return int64(0), res.Err()
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return updated.(int64), nil
+}
@@ -0,0 +1,35 @@
+package persistance
+
+import "time"
+
+// timeParam stores a zero time as null instead of year 1.
+func timeParam(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// toTime decodes a temporal property, treating null as the zero time.
+func toTime(value interface{}) time.Time {
+	if t, ok := value.(time.Time); ok {
+		return t.UTC()
+	}
+	return time.Time{}
+}
+
+// stringParam stores an empty string as null so the property is removed.
+func stringParam(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// toString decodes an optional string property, treating null as the empty string.
+func toString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return ""
+}
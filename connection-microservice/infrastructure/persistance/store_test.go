@@ -0,0 +1,70 @@
+package persistance
+
+import (
+	"connection-microservice/model"
+	"connection-microservice/model/storetest"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"os"
+	"testing"
+)
+
+// testDriver connects to the database at CONNECTION_DB_URI, which tests empty, so it must point at a
+// throwaway database. Tests and benchmarks are skipped when it is not set.
+func testDriver(t testing.TB) neo4j.Driver {
+	t.Helper()
+	uri, found := os.LookupEnv("CONNECTION_DB_URI")
+	if !found {
+		t.Skip("CONNECTION_DB_URI is not set")
+	}
+
+	driver, err := GetDriver(uri, os.Getenv("CONNECTION_DB_USERNAME"), os.Getenv("CONNECTION_DB_PASSWORD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { driver.Close() })
+	return driver
+}
+
+// emptyDatabase removes every node and relationship.
+func emptyDatabase(t testing.TB, driver neo4j.Driver) {
+	t.Helper()
+	session := driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close()
+
+	_, err := session.Run("MATCH (n) DETACH DELETE n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStores(t *testing.T) {
+	driver := testDriver(t)
+	storetest.RunStoreSuite(t, func(t *testing.T) (model.ConnectionStore, model.BlockStore) {
+		emptyDatabase(t, driver)
+		return NewConnectionNeo4jStore(driver), NewBlockNeo4jStore(driver)
+	})
+}
+
+func TestImpressionStore(t *testing.T) {
+	driver := testDriver(t)
+	storetest.RunImpressionStoreSuite(t, func(t *testing.T) model.ImpressionStore {
+		emptyDatabase(t, driver)
+		return NewImpressionNeo4jStore(driver)
+	})
+}
+
+func TestDismissalStore(t *testing.T) {
+	driver := testDriver(t)
+	storetest.RunDismissalStoreSuite(t, func(t *testing.T) (model.ConnectionStore, model.DismissalStore) {
+		emptyDatabase(t, driver)
+		return NewConnectionNeo4jStore(driver), NewDismissalNeo4jStore(driver)
+	})
+}
+
+func BenchmarkSuggestions(b *testing.B) {
+	driver := testDriver(b)
+	storetest.BenchmarkSuggestions(b, func(b *testing.B) (model.ConnectionStore, model.BlockStore) {
+		emptyDatabase(b, driver)
+		return NewConnectionNeo4jStore(driver), NewBlockNeo4jStore(driver)
+	}, 100)
+}
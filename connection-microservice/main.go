@@ -17,9 +17,16 @@ func main() {
 	log.Info("Server starting...")
 
 	config := cfg.NewConfig()
+	if err := config.Validate(); err != nil {
+		log.Fatal("Invalid configuration. Error: " + err.Error())
+	}
+	if err := application.ValidateSuggestionStrategies(config.SuggestionStrategy, config.SuggestionVariants); err != nil {
+		log.Fatal("Invalid configuration. Error: " + err.Error())
+	}
 	server := startup.NewServer(config)
 	server.Start()
-	defer server.Stop()
+	server.WaitForShutdown()
+	server.Stop()
 }
 
 func configuringLog() {
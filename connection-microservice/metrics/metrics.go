@@ -0,0 +1,88 @@
+// Package metrics holds the Prometheus collectors of the service. They are registered with the default
+// registry, which the /metrics endpoint serves.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "connection_service"
+
+// Connection and block events counted by ConnectionEvent and BlockEvent.
+const (
+	ConnectionCreated   = "created"
+	ConnectionApproved  = "approved"
+	ConnectionRejected  = "rejected"
+	ConnectionWithdrawn = "withdrawn"
+	ConnectionDeleted   = "deleted"
+	UserBlocked         = "blocked"
+	UserUnblocked       = "unblocked"
+)
+
+var (
+	rpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of gRPC calls by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	rpcErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_errors_total",
+		Help:      "gRPC calls that returned an error, by method and status code.",
+	}, []string{"method", "code"})
+
+	storeQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "store_query_duration_seconds",
+		Help:      "Latency of store queries by store and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"store", "method"})
+
+	connectionEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "connection_events_total",
+		Help:      "Connections created, approved, rejected, withdrawn and deleted.",
+	}, []string{"event"})
+
+	blockEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "block_events_total",
+		Help:      "Users blocked and unblocked.",
+	}, []string{"event"})
+
+	stalePendingConnections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "stale_pending_connections",
+		Help:      "Pending connection requests older than a day.",
+	})
+)
+
+// ObserveRPC records the latency of a gRPC call and, unless code is OK, counts it as an error.
+func ObserveRPC(method string, code string, seconds float64) {
+	rpcDuration.WithLabelValues(method, code).Observe(seconds)
+	if code != "OK" {
+		rpcErrors.WithLabelValues(method, code).Inc()
+	}
+}
+
+// NewStoreQueryTimer starts timing a store query, which is observed when ObserveDuration is called:
+//
+//	defer metrics.NewStoreQueryTimer("ConnectionNeo4jStore", "CreateConnection").ObserveDuration()
+func NewStoreQueryTimer(store string, method string) *prometheus.Timer {
+	return prometheus.NewTimer(storeQueryDuration.WithLabelValues(store, method))
+}
+
+func ConnectionEvent(event string) {
+	connectionEvents.WithLabelValues(event).Inc()
+}
+
+func BlockEvent(event string) {
+	blockEvents.WithLabelValues(event).Inc()
+}
+
+func SetStalePendingConnections(count int64) {
+	stalePendingConnections.Set(float64(count))
+}
@@ -4,8 +4,16 @@ import "context"
 
 type BlockStore interface {
 	BlockUser(ctx context.Context, block Block) error
+	// BlockAndSever blocks the user and removes CONNECT edges between the two users in both directions
+	// atomically. It returns the removed connections.
+	BlockAndSever(ctx context.Context, block Block) ([]*Connection, error)
 	UnblockUser(ctx context.Context, block Block) error
 	IsBlocked(ctx context.Context, block Block) (bool, error)
 	GetBlocked(ctx context.Context, id string) ([]string, error)
 	GetBlockedBy(ctx context.Context, id string) ([]string, error)
+	// Paged variants order user ids ascending.
+	GetBlockedPage(ctx context.Context, id string, page PageRequest) (*UserIdPage, error)
+	GetBlockedByPage(ctx context.Context, id string, page PageRequest) (*UserIdPage, error)
+	// GetAllBlocksPage pages through every block in the graph ordered by the (user id, blocked user id) pair.
+	GetAllBlocksPage(ctx context.Context, page PageRequest) (*BlockPage, error)
 }
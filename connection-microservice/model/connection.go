@@ -1,6 +1,9 @@
 package model
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"time"
+)
 
 type User struct {
 	UserId primitive.ObjectID
@@ -14,4 +17,12 @@ type Connection struct {
 	IsMessageNotificationEnabled bool
 	IsPostNotificationEnabled    bool
 	IsCommentNotificationEnabled bool
+	CreatedAt                    time.Time
+	RequestedAt                  time.Time
+	ApprovedAt                   time.Time
+	UpdatedAt                    time.Time
+	// ExpiredAt is set when a pending request was not answered in time.
+	ExpiredAt time.Time
+	// Message is the requester's note, kept only while the connection is pending.
+	Message string
 }
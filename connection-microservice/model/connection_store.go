@@ -1,17 +1,68 @@
 package model
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrConnectionNotFound is returned when there is no CONNECT edge between the users.
+var ErrConnectionNotFound = errors.New("connection not found")
 
 type ConnectionStore interface {
 	CreateConnection(ctx context.Context, connection *Connection) (*Connection, error)
+	// UpdateConnection and DeleteConnection return ErrConnectionNotFound when they match no edge.
 	UpdateConnection(ctx context.Context, connection *Connection) (*Connection, error)
 	DeleteConnection(ctx context.Context, userId string, connectedUserId string) error
+	// DeletePendingConnection deletes the connection only if it is still pending and reports whether it did.
+	DeletePendingConnection(ctx context.Context, userId string, connectedUserId string) (bool, error)
+	// GetAllConnectionsByUserId returns the connections from and to the user, leaving out expired requests.
 	GetAllConnectionsByUserId(ctx context.Context, userId string) ([]*Connection, error)
+	// GetConnectionByUsersId returns ErrConnectionNotFound when there is no edge from userId to connectedUserId.
 	GetConnectionByUsersId(ctx context.Context, userId string, connectedUserId string) (*Connection, error)
 	GetFollowings(ctx context.Context, userId string) ([]*Connection, error)
 	GetFollowers(ctx context.Context, connectedUserId string) ([]*Connection, error)
 	GetAllRequestConnectionsByUserId(ctx context.Context, userId string) ([]*Connection, error)
 	GetAllPendingConnectionsByUserId(ctx context.Context, userId string) ([]*Connection, error)
+	// Paged variants order followings and pending connections by connected user id, followers and
+	// requests by user id and all connections by the (user id, connected user id) pair.
+	// GetAllConnectionsPage pages the connections of GetAllConnectionsByUserId by (user id, connected user id).
+	GetAllConnectionsPage(ctx context.Context, userId string, page PageRequest) (*ConnectionPage, error)
+	GetFollowingsPage(ctx context.Context, userId string, page PageRequest) (*ConnectionPage, error)
+	GetFollowersPage(ctx context.Context, connectedUserId string, page PageRequest) (*ConnectionPage, error)
+	GetAllRequestConnectionsPage(ctx context.Context, userId string, page PageRequest) (*ConnectionPage, error)
+	GetAllPendingConnectionsPage(ctx context.Context, userId string, page PageRequest) (*ConnectionPage, error)
+	GetAllExpiredConnectionsPage(ctx context.Context, userId string, page PageRequest) (*ConnectionPage, error)
+	// ExpirePendingConnections marks pending connections requested before requestedBefore as expired
+	// and returns them.
+	ExpirePendingConnections(ctx context.Context, requestedBefore time.Time) ([]*Connection, error)
+	// CountPendingConnections counts pending connections requested before requestedBefore.
+	CountPendingConnections(ctx context.Context, requestedBefore time.Time) (int64, error)
+	// GetMutualConnections counts users followed by both users, leaving out excludedUserIds, and samples up to
+	// sampleSize of their ids in ascending order.
+	GetMutualConnections(ctx context.Context, userId string, otherUserId string, excludedUserIds []string, sampleSize int) (*MutualConnections, error)
+	// GetDegrees finds, for every target, the shortest chain of approved connections from userId of at most
+	// maxDepth hops that does not pass through excludedUserIds. Targets that are unknown or equal to userId are left out.
+	GetDegrees(ctx context.Context, userId string, targetUserIds []string, excludedUserIds []string, maxDepth int, withPath bool) ([]*Degree, error)
+	// GetSuggestionCandidates returns up to limit users followed by the user's followings, leaving out the user,
+	// users with any connection from the user, users blocked by or blocking the user and users the user dismissed.
+	// Candidates are ordered by how many followings follow them, then by user id.
+	GetSuggestionCandidates(ctx context.Context, userId string, limit int) ([]*SuggestionCandidate, error)
+	// GetPopularUsers returns up to limit of the most followed users with the same exclusions as GetSuggestionCandidates.
+	// MutualCount and AdamicAdar are not filled.
+	GetPopularUsers(ctx context.Context, userId string, limit int) ([]*SuggestionCandidate, error)
 	GetFollowingsOfMyFollowings(ctx context.Context, connectedUserId string, userId string) ([]string, error)
-	GetRandom(ctx context.Context, userId string, limit int) ([]string, error)
+	// GetMostFollowedUsers returns up to limit of all users ordered by FollowerCount, then by user id. Unlike
+	// GetPopularUsers it leaves nobody out. MutualCount and AdamicAdar are not filled.
+	GetMostFollowedUsers(ctx context.Context, limit int) ([]*SuggestionCandidate, error)
+	// GetRecentlyActiveUsers returns up to limit users ordered by their latest connection change, then by user id.
+	GetRecentlyActiveUsers(ctx context.Context, limit int) ([]string, error)
+	// GetRandomUsers returns a sample of up to limit of all users picked by model.SampleUserIds with seed.
+	GetRandomUsers(ctx context.Context, limit int, seed int64) ([]string, error)
+	// GetSuggestionExclusions returns the users GetSuggestionCandidates leaves out for the user, apart from the
+	// user itself, so lists fetched without exclusions can be filtered in memory.
+	GetSuggestionExclusions(ctx context.Context, userId string) ([]string, error)
+	// DeleteUserEdges removes every relationship of the user whatever its type or direction: connections, blocks,
+	// dismissals and suggestion impressions. The user node stays. It returns how many relationships were removed.
+	DeleteUserEdges(ctx context.Context, userId string) (int64, error)
 }
@@ -0,0 +1,10 @@
+package model
+
+// Degree is the length of the shortest chain of connections from a user to UserId.
+// A Degree of 0 means UserId is not reachable within the maximum depth.
+type Degree struct {
+	UserId string
+	Degree int
+	// Path holds the user ids along one shortest chain, both ends included, when it was asked for.
+	Path []string
+}
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Dismissal keeps DismissedUserId out of the suggestions of UserId until ExpiresAt.
+type Dismissal struct {
+	UserId          string
+	DismissedUserId string
+	DismissedAt     time.Time
+	ExpiresAt       time.Time
+}
+
+type DismissalPage struct {
+	Dismissals []*Dismissal
+	NextCursor string
+}
+
+// NewDismissalPage builds a page from dismissals fetched with a limit of size+1, ordered by dismissed user id.
+func NewDismissalPage(dismissals []*Dismissal, size int) *DismissalPage {
+	page := &DismissalPage{Dismissals: dismissals}
+	if len(dismissals) > size {
+		page.Dismissals = dismissals[:size]
+		page.NextCursor = EncodeCursor(dismissals[size-1].DismissedUserId)
+	}
+	return page
+}
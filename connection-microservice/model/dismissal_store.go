@@ -0,0 +1,21 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// DismissalStore keeps DISMISSED edges. Suggestion queries of ConnectionStore skip users with a
+// DISMISSED edge from the user that has not expired yet.
+type DismissalStore interface {
+	// DismissSuggestion creates the DISMISSED edge or renews it.
+	DismissSuggestion(ctx context.Context, dismissal Dismissal) error
+	// UndoDismissal removes the DISMISSED edge and reports whether there was one that had not expired.
+	UndoDismissal(ctx context.Context, userId string, dismissedUserId string) (bool, error)
+	// GetDismissalsPage lists dismissals of the user that have not expired, ordered by dismissed user id.
+	GetDismissalsPage(ctx context.Context, userId string, page PageRequest) (*DismissalPage, error)
+	// GetDismissedUserIds returns every user the user dismissed that has not expired.
+	GetDismissedUserIds(ctx context.Context, userId string) ([]string, error)
+	// DeleteExpiredDismissals removes DISMISSED edges that expired before now and returns how many.
+	DeleteExpiredDismissals(ctx context.Context, now time.Time) (int64, error)
+}
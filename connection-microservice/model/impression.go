@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// SuggestionImpression records that SuggestedUserId was shown to UserId by a variant of an experiment.
+// Showing the same suggestion again bumps Count and LastShownAt.
+type SuggestionImpression struct {
+	UserId          string
+	SuggestedUserId string
+	Experiment      string
+	Variant         string
+	Count           int64
+	LastShownAt     time.Time
+	// AcceptedAt is set once the user connects to the suggested user.
+	AcceptedAt time.Time
+}
+
+// VariantStats sums up the impressions of one variant. Suggestions counts distinct suggested users per
+// user, so AcceptanceRate is Acceptances / Suggestions.
+type VariantStats struct {
+	Experiment     string
+	Variant        string
+	Impressions    int64
+	Suggestions    int64
+	Acceptances    int64
+	AcceptanceRate float64
+}
+
+func NewVariantStats(experiment string, variant string, impressions int64, suggestions int64, acceptances int64) *VariantStats {
+	stats := &VariantStats{
+		Experiment:  experiment,
+		Variant:     variant,
+		Impressions: impressions,
+		Suggestions: suggestions,
+		Acceptances: acceptances,
+	}
+	if suggestions > 0 {
+		stats.AcceptanceRate = float64(acceptances) / float64(suggestions)
+	}
+	return stats
+}
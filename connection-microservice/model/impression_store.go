@@ -0,0 +1,16 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+type ImpressionStore interface {
+	// RecordImpressions stores one SUGGESTED edge per user, suggested user, experiment and variant.
+	RecordImpressions(ctx context.Context, impressions []*SuggestionImpression) error
+	// AcceptImpression marks the most recently shown, not yet accepted impression of suggestedUserId to
+	// userId shown at or after shownAfter. It returns nil when there is none.
+	AcceptImpression(ctx context.Context, userId string, suggestedUserId string, shownAfter time.Time, acceptedAt time.Time) (*SuggestionImpression, error)
+	// GetVariantStats returns the stats of every variant of the experiment ordered by variant.
+	GetVariantStats(ctx context.Context, experiment string) ([]*VariantStats, error)
+}
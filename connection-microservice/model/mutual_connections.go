@@ -0,0 +1,7 @@
+package model
+
+// MutualConnections are users followed by both users: their total count and a sample of their ids.
+type MutualConnections struct {
+	Count   int64
+	UserIds []string
+}
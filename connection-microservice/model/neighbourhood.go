@@ -0,0 +1,12 @@
+package model
+
+// Neighbourhood is the relationships of a user as moderators see it.
+type Neighbourhood struct {
+	UserId           string
+	Followings       []*Connection
+	Followers        []*Connection
+	SentRequests     []*Connection
+	ReceivedRequests []*Connection
+	Blocked          []string
+	BlockedBy        []string
+}
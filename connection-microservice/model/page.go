@@ -0,0 +1,85 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest asks for at most Size items following the item the opaque Cursor points to.
+// An empty Cursor starts from the first item.
+type PageRequest struct {
+	Cursor string
+	Size   int
+}
+
+type ConnectionPage struct {
+	Connections []*Connection
+	NextCursor  string
+}
+
+type UserIdPage struct {
+	UserIds    []string
+	NextCursor string
+}
+
+type BlockPage struct {
+	Blocks     []Block
+	NextCursor string
+}
+
+// EncodeCursor turns the sort key of the last item on a page into an opaque cursor.
+func EncodeCursor(keys ...string) string {
+	data, _ := json.Marshal(keys)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor returns the sort key stored in cursor. An empty cursor decodes to n empty strings,
+// which sort before every user id.
+func DecodeCursor(cursor string, n int) ([]string, error) {
+	if cursor == "" {
+		return make([]string, n), nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil || len(keys) != n {
+		return nil, ErrInvalidCursor
+	}
+	return keys, nil
+}
+
+// NewConnectionPage builds a page from connections fetched with a limit of size+1,
+// the extra connection only signalling that there is a next page.
+func NewConnectionPage(connections []*Connection, size int, key func(connection *Connection) []string) *ConnectionPage {
+	page := &ConnectionPage{Connections: connections}
+	if len(connections) > size {
+		page.Connections = connections[:size]
+		page.NextCursor = EncodeCursor(key(connections[size-1])...)
+	}
+	return page
+}
+
+// NewUserIdPage builds a page from user ids fetched with a limit of size+1.
+func NewUserIdPage(userIds []string, size int) *UserIdPage {
+	page := &UserIdPage{UserIds: userIds}
+	if len(userIds) > size {
+		page.UserIds = userIds[:size]
+		page.NextCursor = EncodeCursor(userIds[size-1])
+	}
+	return page
+}
+
+// NewBlockPage builds a page from blocks fetched with a limit of size+1, keyed by the (user id, blocked user id) pair.
+func NewBlockPage(blocks []Block, size int) *BlockPage {
+	page := &BlockPage{Blocks: blocks}
+	if len(blocks) > size {
+		page.Blocks = blocks[:size]
+		page.NextCursor = EncodeCursor(blocks[size-1].UserId, blocks[size-1].BlockedUserId)
+	}
+	return page
+}
@@ -0,0 +1,77 @@
+package storetest
+
+import (
+	"connection-microservice/model"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// BenchmarkSuggestions compares fetching suggestion candidates one following at a time, checking blocks
+// per candidate, with the single GetSuggestionCandidates query. Call it from a backend's benchmarks:
+//
+//	func BenchmarkSuggestions(b *testing.B) {
+//		storetest.BenchmarkSuggestions(b, factory, 500)
+//	}
+//
+// The gap grows with the round trip time of the backend, so it is most telling against Neo4j.
+func BenchmarkSuggestions(b *testing.B, factory func(b *testing.B) (model.ConnectionStore, model.BlockStore), followings int) {
+	ctx := context.Background()
+	store, blockStore := factory(b)
+	seedSuggestionGraph(b, store, blockStore, followings)
+
+	b.Run("PerFollowing", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			connections, err := store.GetFollowings(ctx, "user")
+			if err != nil {
+				b.Fatal(err)
+			}
+			candidates := map[string]bool{}
+			for _, connection := range connections {
+				users, err := store.GetFollowingsOfMyFollowings(ctx, connection.ConnectedUserId, "user")
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, user := range users {
+					candidates[user] = true
+				}
+			}
+			for candidate := range candidates {
+				if _, err := blockStore.IsBlocked(ctx, model.Block{UserId: "user", BlockedUserId: candidate}); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := blockStore.IsBlocked(ctx, model.Block{UserId: candidate, BlockedUserId: "user"}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("SingleQuery", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := store.GetSuggestionCandidates(ctx, "user", 15); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// seedSuggestionGraph makes "user" follow n users that each follow ten of a pool of 2n users.
+func seedSuggestionGraph(b *testing.B, store model.ConnectionStore, blockStore model.BlockStore, n int) {
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		following := fmt.Sprintf("following%d", i)
+		if _, err := store.CreateConnection(ctx, &model.Connection{UserId: "user", ConnectedUserId: following, IsConnected: true}); err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < 10; j++ {
+			candidate := fmt.Sprintf("candidate%d", (i*7+j)%(2*n))
+			if _, err := store.CreateConnection(ctx, &model.Connection{UserId: following, ConnectedUserId: candidate, IsConnected: true}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	if err := blockStore.BlockUser(ctx, model.Block{UserId: "user", BlockedUserId: "candidate0"}); err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,183 @@
+package storetest
+
+import (
+	"connection-microservice/model"
+	"context"
+	"testing"
+)
+
+func RunBlockStoreSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("BlockIsDirected", func(t *testing.T) {
+		_, store := factory(t)
+		block(t, store, "a", "b")
+
+		blocked, err := store.IsBlocked(ctx, model.Block{UserId: "a", BlockedUserId: "b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !blocked {
+			t.Fatal("b must be blocked by a")
+		}
+
+		blocked, err = store.IsBlocked(ctx, model.Block{UserId: "b", BlockedUserId: "a"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blocked {
+			t.Fatal("a must not be blocked by b")
+		}
+	})
+
+	t.Run("BlockedAndBlockedByAreSymmetric", func(t *testing.T) {
+		_, store := factory(t)
+		block(t, store, "a", "b")
+		block(t, store, "a", "c")
+		block(t, store, "c", "b")
+
+		blocked, err := store.GetBlocked(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "blocked by a", sorted(blocked), "b", "c")
+
+		blockedBy, err := store.GetBlockedBy(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "users blocking b", sorted(blockedBy), "a", "c")
+
+		blockedBy, err = store.GetBlockedBy(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "users blocking a", sorted(blockedBy))
+	})
+
+	t.Run("BlockTwiceAndUnblock", func(t *testing.T) {
+		_, store := factory(t)
+		block(t, store, "a", "b")
+		block(t, store, "a", "b")
+
+		blocked, err := store.GetBlocked(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "blocked by a", sorted(blocked), "b")
+
+		if err := store.UnblockUser(ctx, model.Block{UserId: "a", BlockedUserId: "b"}); err != nil {
+			t.Fatal(err)
+		}
+		isBlocked, err := store.IsBlocked(ctx, model.Block{UserId: "a", BlockedUserId: "b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if isBlocked {
+			t.Fatal("b must not be blocked after unblocking")
+		}
+		if err := store.UnblockUser(ctx, model.Block{UserId: "a", BlockedUserId: "b"}); err != nil {
+			t.Fatalf("unblocking a missing block: %v", err)
+		}
+	})
+
+	t.Run("BlockAndSever", func(t *testing.T) {
+		connectionStore, store := factory(t)
+		connect(t, connectionStore, "a", "b")
+		request(t, connectionStore, "b", "a")
+		connect(t, connectionStore, "a", "c")
+
+		removed, err := store.BlockAndSever(ctx, model.Block{UserId: "a", BlockedUserId: "b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "removed connections", pairs(removed), "a->b", "b->a")
+
+		blocked, err := store.IsBlocked(ctx, model.Block{UserId: "a", BlockedUserId: "b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !blocked {
+			t.Fatal("b must be blocked by a")
+		}
+
+		all, err := connectionStore.GetAllConnectionsByUserId(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "all connections of a", pairs(all), "a->c")
+
+		removed, err = store.BlockAndSever(ctx, model.Block{UserId: "a", BlockedUserId: "b"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "removed connections on second block", pairs(removed))
+	})
+
+	t.Run("BlockedUsersShareTheGraph", func(t *testing.T) {
+		connectionStore, store := factory(t)
+		block(t, store, "a", "b")
+		connect(t, connectionStore, "c", "d")
+
+		users, err := connectionStore.GetRandomUsers(ctx, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "random users", sorted(users), "a", "b", "c", "d")
+
+		users, err = connectionStore.GetSuggestionExclusions(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "suggestion exclusions of b", sorted(users), "a")
+	})
+	t.Run("Pagination", func(t *testing.T) {
+		_, store := factory(t)
+		block(t, store, "a", "d")
+		block(t, store, "a", "b")
+		block(t, store, "a", "c")
+
+		page, err := store.GetBlockedPage(ctx, "a", model.PageRequest{Size: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "first page blocked by a", page.UserIds, "b", "c")
+
+		page, err = store.GetBlockedPage(ctx, "a", model.PageRequest{Size: 2, Cursor: page.NextCursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "second page blocked by a", page.UserIds, "d")
+		if page.NextCursor != "" {
+			t.Fatal("the last page must not have a next cursor")
+		}
+
+		page, err = store.GetBlockedByPage(ctx, "c", model.PageRequest{Size: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "users blocking c", page.UserIds, "a")
+	})
+
+	t.Run("AllBlocksPagination", func(t *testing.T) {
+		_, store := factory(t)
+		block(t, store, "b", "a")
+		block(t, store, "a", "c")
+		block(t, store, "a", "b")
+
+		page, err := store.GetAllBlocksPage(ctx, model.PageRequest{Size: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "first page of blocks", blockPairs(page.Blocks), "a->b", "a->c")
+
+		page, err = store.GetAllBlocksPage(ctx, model.PageRequest{Size: 2, Cursor: page.NextCursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "second page of blocks", blockPairs(page.Blocks), "b->a")
+		if page.NextCursor != "" {
+			t.Fatal("the last page must not have a next cursor")
+		}
+	})
+}
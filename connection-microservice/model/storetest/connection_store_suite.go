@@ -0,0 +1,741 @@
+package storetest
+
+import (
+	"connection-microservice/model"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func RunConnectionStoreSuite(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run("CreateEnablesNotifications", func(t *testing.T) {
+		store, _ := factory(t)
+		request(t, store, "a", "b")
+
+		connection, err := store.GetConnectionByUsersId(ctx, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if connection.UserId != "a" || connection.ConnectedUserId != "b" {
+			t.Fatalf("got connection %s->%s, want a->b", connection.UserId, connection.ConnectedUserId)
+		}
+		if connection.IsConnected || !connection.PendingConnection {
+			t.Fatalf("got isConnected=%v pendingConnection=%v, want false/true", connection.IsConnected, connection.PendingConnection)
+		}
+		if !connection.IsMessageNotificationEnabled || !connection.IsPostNotificationEnabled || !connection.IsCommentNotificationEnabled {
+			t.Fatal("notifications must be enabled on a new connection")
+		}
+	})
+
+	t.Run("Timestamps", func(t *testing.T) {
+		store, _ := factory(t)
+		before := time.Now().Add(-time.Second)
+		request(t, store, "a", "b")
+		connect(t, store, "a", "c")
+
+		connection, err := store.GetConnectionByUsersId(ctx, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if connection.CreatedAt.Before(before) || connection.RequestedAt.Before(before) || connection.UpdatedAt.Before(before) {
+			t.Fatalf("createdAt, requestedAt and updatedAt must be set on a new request: %+v", *connection)
+		}
+		if !connection.ApprovedAt.IsZero() {
+			t.Fatal("approvedAt must not be set on a pending request")
+		}
+
+		approvedAt := time.Now().UTC()
+		connection.IsConnected = true
+		connection.PendingConnection = false
+		connection.ApprovedAt = approvedAt
+		if _, err := store.UpdateConnection(ctx, connection); err != nil {
+			t.Fatal(err)
+		}
+		approved, err := store.GetConnectionByUsersId(ctx, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !approved.ApprovedAt.Equal(approvedAt) || !approved.CreatedAt.Equal(connection.CreatedAt) || approved.UpdatedAt.Before(connection.CreatedAt) {
+			t.Fatalf("approval must keep createdAt and store approvedAt: %+v", *approved)
+		}
+
+		connected, err := store.GetConnectionByUsersId(ctx, "a", "c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if connected.ApprovedAt.Before(before) || !connected.RequestedAt.IsZero() {
+			t.Fatalf("a connection to a public user is approved immediately: %+v", *connected)
+		}
+	})
+
+	t.Run("RequestMessage", func(t *testing.T) {
+		store, _ := factory(t)
+		_, err := store.CreateConnection(ctx, &model.Connection{UserId: "a", ConnectedUserId: "b", PendingConnection: true, Message: "hi"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		requests, err := store.GetAllRequestConnectionsByUserId(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(requests) != 1 || requests[0].Message != "hi" {
+			t.Fatalf("the request to b must carry its message, got %v", pairs(requests))
+		}
+
+		connection := requests[0]
+		connection.IsConnected = true
+		connection.PendingConnection = false
+		connection.Message = ""
+		if _, err := store.UpdateConnection(ctx, connection); err != nil {
+			t.Fatal(err)
+		}
+		approved, err := store.GetConnectionByUsersId(ctx, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if approved.Message != "" {
+			t.Fatalf("the message must be dropped, got %q", approved.Message)
+		}
+	})
+
+	t.Run("GetMissingConnection", func(t *testing.T) {
+		store, _ := factory(t)
+
+		_, err := store.GetConnectionByUsersId(ctx, "a", "b")
+		if !errors.Is(err, model.ErrConnectionNotFound) {
+			t.Fatalf("got %v, want ErrConnectionNotFound", err)
+		}
+
+		_, err = store.UpdateConnection(ctx, &model.Connection{UserId: "a", ConnectedUserId: "b", IsConnected: true})
+		if !errors.Is(err, model.ErrConnectionNotFound) {
+			t.Fatalf("updating a missing connection: got %v, want ErrConnectionNotFound", err)
+		}
+		if _, err := store.GetConnectionByUsersId(ctx, "a", "b"); !errors.Is(err, model.ErrConnectionNotFound) {
+			t.Fatal("updating a missing connection must not create it")
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		store, _ := factory(t)
+		request(t, store, "a", "b")
+
+		_, err := store.UpdateConnection(ctx, &model.Connection{UserId: "a", ConnectedUserId: "b", IsConnected: true, IsPostNotificationEnabled: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		connection, err := store.GetConnectionByUsersId(ctx, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !connection.IsConnected || connection.PendingConnection {
+			t.Fatalf("got isConnected=%v pendingConnection=%v, want true/false", connection.IsConnected, connection.PendingConnection)
+		}
+		if connection.IsMessageNotificationEnabled || !connection.IsPostNotificationEnabled || connection.IsCommentNotificationEnabled {
+			t.Fatalf("notification flags were not updated: %+v", *connection)
+		}
+
+		_, err = store.GetConnectionByUsersId(ctx, "b", "a")
+		if !errors.Is(err, model.ErrConnectionNotFound) {
+			t.Fatal("update must not create the reverse edge")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		store, _ := factory(t)
+		connect(t, store, "a", "b")
+		connect(t, store, "b", "a")
+
+		if err := store.DeleteConnection(ctx, "a", "b"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.DeleteConnection(ctx, "a", "b"); !errors.Is(err, model.ErrConnectionNotFound) {
+			t.Fatalf("deleting a missing connection: got %v, want ErrConnectionNotFound", err)
+		}
+
+		followings, err := store.GetFollowings(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followings of a", pairs(followings))
+
+		followings, err = store.GetFollowings(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followings of b", pairs(followings), "b->a")
+	})
+
+	t.Run("DeleteUserEdges", func(t *testing.T) {
+		store, blockStore := factory(t)
+		connect(t, store, "a", "b")
+		request(t, store, "c", "a")
+		connect(t, store, "b", "c")
+		block(t, blockStore, "d", "a")
+
+		deleted, err := store.DeleteUserEdges(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted != 3 {
+			t.Fatalf("deleted %d edges, want 3", deleted)
+		}
+
+		connections, err := store.GetAllConnectionsByUserId(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "connections of a", pairs(connections))
+
+		blockedBy, err := blockStore.GetBlockedBy(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "users blocking a", blockedBy)
+
+		followings, err := store.GetFollowings(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followings of b", pairs(followings), "b->c")
+	})
+
+	t.Run("DeletePendingConnection", func(t *testing.T) {
+		store, _ := factory(t)
+		request(t, store, "a", "b")
+		connect(t, store, "a", "c")
+
+		deleted, err := store.DeletePendingConnection(ctx, "b", "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted {
+			t.Fatal("the receiver's direction must not match a pending request")
+		}
+
+		deleted, err = store.DeletePendingConnection(ctx, "a", "c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted {
+			t.Fatal("an approved connection must not be deleted")
+		}
+
+		deleted, err = store.DeletePendingConnection(ctx, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !deleted {
+			t.Fatal("the pending request must be deleted")
+		}
+
+		all, err := store.GetAllConnectionsByUserId(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "all connections of a", pairs(all), "a->c")
+	})
+
+	t.Run("Directionality", func(t *testing.T) {
+		store, _ := factory(t)
+		connect(t, store, "a", "b")
+		connect(t, store, "a", "c")
+		connect(t, store, "c", "b")
+		request(t, store, "d", "a")
+
+		followings, err := store.GetFollowings(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followings of a", pairs(followings), "a->b", "a->c")
+
+		followers, err := store.GetFollowers(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followers of b", pairs(followers), "a->b", "c->b")
+
+		followings, err = store.GetFollowings(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followings of b", pairs(followings))
+
+		followers, err = store.GetFollowers(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followers of a", pairs(followers))
+
+		all, err := store.GetAllConnectionsByUserId(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "all connections of a", pairs(all), "a->b", "a->c", "d->a")
+	})
+
+	t.Run("RequestAndPendingFilters", func(t *testing.T) {
+		store, _ := factory(t)
+		request(t, store, "a", "b")
+		request(t, store, "c", "b")
+		request(t, store, "b", "d")
+		connect(t, store, "e", "b")
+		connect(t, store, "b", "e")
+
+		requests, err := store.GetAllRequestConnectionsByUserId(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "requests to b", pairs(requests), "a->b", "c->b")
+
+		pending, err := store.GetAllPendingConnectionsByUserId(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "pending of b", pairs(pending), "b->d")
+
+		pending, err = store.GetAllPendingConnectionsByUserId(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "pending of a", pairs(pending), "a->b")
+
+		followers, err := store.GetFollowers(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followers of b", pairs(followers), "e->b")
+	})
+
+	t.Run("FollowingsOfMyFollowings", func(t *testing.T) {
+		store, _ := factory(t)
+		connect(t, store, "a", "b")
+		connect(t, store, "a", "d")
+		connect(t, store, "b", "c")
+		connect(t, store, "b", "d")
+		request(t, store, "b", "e")
+
+		users, err := store.GetFollowingsOfMyFollowings(ctx, "b", "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "followings of b not followed by a", sorted(users), "c")
+	})
+
+	t.Run("RecentlyActiveUsers", func(t *testing.T) {
+		store, _ := factory(t)
+		connect(t, store, "a", "b")
+		time.Sleep(10 * time.Millisecond)
+		connect(t, store, "d", "c")
+		time.Sleep(10 * time.Millisecond)
+		connect(t, store, "e", "c")
+
+		users, err := store.GetRecentlyActiveUsers(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(users, ",") != "e,d,a" {
+			t.Fatalf("got %v, want [e d a]", users)
+		}
+
+		users, err = store.GetRecentlyActiveUsers(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 1 || users[0] != "e" {
+			t.Fatalf("got %v, want the most recently active user", users)
+		}
+	})
+
+	t.Run("RandomUsers", func(t *testing.T) {
+		store, blockStore := factory(t)
+		connect(t, store, "a", "b")
+		request(t, store, "a", "c")
+		for i := 0; i < 5; i++ {
+			connect(t, store, "x", fmt.Sprintf("u%d", i))
+		}
+		block(t, blockStore, "a", "u0")
+
+		users, err := store.GetRandomUsers(ctx, 100, 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "random users", sorted(users), "a", "b", "c", "u0", "u1", "u2", "u3", "u4", "x")
+
+		again, err := store.GetRandomUsers(ctx, 3, 42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(again) != 3 || again[0] != users[0] || again[1] != users[1] || again[2] != users[2] {
+			t.Fatalf("got %v, want the first three of %v for the same seed", again, users)
+		}
+	})
+
+	t.Run("RandomUsersSeeds", func(t *testing.T) {
+		store, _ := factory(t)
+		for i := 0; i < 12; i++ {
+			connect(t, store, "x", fmt.Sprintf("u%02d", i))
+		}
+
+		first, err := store.GetRandomUsers(ctx, 100, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := store.GetRandomUsers(ctx, 100, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "random users for seed 2", sorted(second), sorted(first)...)
+		for shift := 0; shift < len(first); shift++ {
+			rotated := append(append([]string{}, first[shift:]...), first[:shift]...)
+			if strings.Join(rotated, ",") == strings.Join(second, ",") {
+				t.Fatalf("got %v for seed 2, a rotation of %v for seed 1", second, first)
+			}
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		store, _ := factory(t)
+		connect(t, store, "u3", "z")
+		connect(t, store, "u1", "z")
+		connect(t, store, "u5", "z")
+		connect(t, store, "u2", "z")
+		connect(t, store, "u4", "z")
+		request(t, store, "z", "u1")
+
+		var followers []*model.Connection
+		page := model.PageRequest{Size: 2}
+		for i := 0; ; i++ {
+			if i > 5 {
+				t.Fatal("pagination did not terminate")
+			}
+			result, err := store.GetFollowersPage(ctx, "z", page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Connections) > 2 {
+				t.Fatalf("got %d followers on a page of size 2", len(result.Connections))
+			}
+			followers = append(followers, result.Connections...)
+			if result.NextCursor == "" {
+				break
+			}
+			page.Cursor = result.NextCursor
+		}
+		got := []string{}
+		for _, connection := range followers {
+			got = append(got, connection.UserId)
+		}
+		want := []string{"u1", "u2", "u3", "u4", "u5"}
+		if len(got) != len(want) {
+			t.Fatalf("followers across pages: got %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("followers across pages: got %v, want %v", got, want)
+			}
+		}
+
+		all, err := store.GetAllConnectionsPage(ctx, "z", model.PageRequest{Size: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if all.NextCursor != "" {
+			t.Fatal("a page holding every connection must not have a next cursor")
+		}
+		assertEqual(t, "all connections of z", pairs(all.Connections), "u1->z", "u2->z", "u3->z", "u4->z", "u5->z", "z->u1")
+
+		all, err = store.GetAllConnectionsPage(ctx, "z", model.PageRequest{Size: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		all, err = store.GetAllConnectionsPage(ctx, "z", model.PageRequest{Size: 5, Cursor: all.NextCursor})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "second page of all connections of z", pairs(all.Connections), "z->u1")
+
+		if _, err := store.GetFollowingsPage(ctx, "z", model.PageRequest{Size: 2, Cursor: "not a cursor"}); err == nil {
+			t.Fatal("an invalid cursor must be rejected")
+		}
+	})
+	t.Run("ExpirePendingConnections", func(t *testing.T) {
+		store, _ := factory(t)
+		request(t, store, "a", "b")
+		request(t, store, "c", "b")
+		connect(t, store, "a", "c")
+
+		pendingCount, err := store.CountPendingConnections(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pendingCount != 2 {
+			t.Fatalf("counted %d pending requests, want 2", pendingCount)
+		}
+
+		expired, err := store.ExpirePendingConnections(ctx, time.Now().Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "expired fresh requests", pairs(expired))
+
+		expired, err = store.ExpirePendingConnections(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "expired requests", pairs(expired), "a->b", "c->b")
+		if expired[0].PendingConnection || expired[0].ExpiredAt.IsZero() {
+			t.Fatal("expired requests must be returned as expired")
+		}
+
+		all, err := store.GetAllConnectionsByUserId(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "all connections of b", pairs(all))
+
+		allPage, err := store.GetAllConnectionsPage(ctx, "a", model.PageRequest{Size: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "all connections page of a", pairs(allPage.Connections), "a->c")
+
+		excluded, err := store.GetSuggestionExclusions(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "suggestion exclusions of a", sorted(excluded), "c")
+
+		pendingCount, err = store.CountPendingConnections(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pendingCount != 0 {
+			t.Fatalf("counted %d pending requests after expiring them, want 0", pendingCount)
+		}
+
+		pending, err := store.GetAllPendingConnectionsByUserId(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "pending of a", pairs(pending))
+
+		page, err := store.GetAllExpiredConnectionsPage(ctx, "a", model.PageRequest{Size: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "expired of a", pairs(page.Connections), "a->b")
+		if page.Connections[0].ExpiredAt.IsZero() {
+			t.Fatal("expiredAt must be set on an expired request")
+		}
+
+		request(t, store, "a", "b")
+		page, err = store.GetAllExpiredConnectionsPage(ctx, "a", model.PageRequest{Size: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "expired of a after requesting again", pairs(page.Connections))
+
+		requests, err := store.GetAllRequestConnectionsByUserId(ctx, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "requests to b", pairs(requests), "a->b")
+	})
+	t.Run("MutualConnections", func(t *testing.T) {
+		store, _ := factory(t)
+		for _, id := range []string{"m1", "m2", "m3", "m4"} {
+			connect(t, store, "a", id)
+			connect(t, store, "b", id)
+		}
+		connect(t, store, "a", "x")
+		request(t, store, "b", "x")
+		connect(t, store, "y", "a")
+		connect(t, store, "y", "b")
+
+		mutual, err := store.GetMutualConnections(ctx, "a", "b", []string{"m2"}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mutual.Count != 3 {
+			t.Fatalf("got %d mutual connections, want 3", mutual.Count)
+		}
+		assertEqual(t, "mutual sample", mutual.UserIds, "m1", "m3")
+
+		mutual, err = store.GetMutualConnections(ctx, "a", "c", []string{}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mutual.Count != 0 || len(mutual.UserIds) != 0 {
+			t.Fatalf("got %+v, want no mutual connections", *mutual)
+		}
+	})
+	t.Run("Degrees", func(t *testing.T) {
+		store, _ := factory(t)
+		connect(t, store, "a", "b")
+		connect(t, store, "b", "c")
+		connect(t, store, "c", "d")
+		connect(t, store, "a", "x")
+		connect(t, store, "x", "y")
+		request(t, store, "y", "z")
+		connect(t, store, "e", "a")
+
+		degrees, err := store.GetDegrees(ctx, "a", []string{"b", "c", "d", "z", "e", "a", "unknown"}, []string{}, 3, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]*model.Degree{}
+		for _, degree := range degrees {
+			got[degree.UserId] = degree
+		}
+		if len(got) != 5 {
+			t.Fatalf("got degrees for %d targets, want 5 without a and unknown users", len(got))
+		}
+		for id, want := range map[string]int{"b": 1, "c": 2, "d": 3, "z": 0, "e": 0} {
+			if got[id].Degree != want {
+				t.Fatalf("degree of %s: got %d, want %d", id, got[id].Degree, want)
+			}
+		}
+		assertEqual(t, "path to d", sorted(got["d"].Path), "a", "b", "c", "d")
+		if got["d"].Path[0] != "a" || got["d"].Path[3] != "d" {
+			t.Fatalf("path must start at a and end at d, got %v", got["d"].Path)
+		}
+
+		degrees, err = store.GetDegrees(ctx, "a", []string{"d"}, []string{}, 2, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(degrees) != 1 || degrees[0].Degree != 0 || degrees[0].Path != nil {
+			t.Fatal("d is out of reach at depth 2")
+		}
+
+		connect(t, store, "x", "c")
+		degrees, err = store.GetDegrees(ctx, "a", []string{"c"}, []string{"b"}, 3, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(degrees) != 1 || degrees[0].Degree != 2 || degrees[0].Path[1] != "x" {
+			t.Fatalf("the chain must avoid excluded users, got %+v", degrees)
+		}
+	})
+	t.Run("SuggestionCandidates", func(t *testing.T) {
+		store, blockStore := factory(t)
+		connect(t, store, "a", "b")
+		connect(t, store, "a", "c")
+		connect(t, store, "b", "x")
+		connect(t, store, "c", "x")
+		connect(t, store, "b", "y")
+		connect(t, store, "b", "c")
+		connect(t, store, "b", "a")
+		connect(t, store, "c", "p")
+		request(t, store, "a", "p")
+		connect(t, store, "c", "q")
+		block(t, blockStore, "q", "a")
+		request(t, store, "b", "z")
+
+		candidates, err := store.GetSuggestionCandidates(ctx, "a", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, candidate := range candidates {
+			got = append(got, fmt.Sprintf("%s:%d:%d", candidate.UserId, candidate.MutualCount, candidate.FollowerCount))
+		}
+		want := []string{"x:2:2", "y:1:1"}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Fatalf("got candidates %v, want %v", got, want)
+		}
+		// b follows 4 users and c follows 3
+		adamicAdar := 1/math.Log(5) + 1/math.Log(4)
+		if math.Abs(candidates[0].AdamicAdar-adamicAdar) > 1e-9 || math.Abs(candidates[1].AdamicAdar-1/math.Log(5)) > 1e-9 {
+			t.Fatalf("got Adamic-Adar %v and %v, want %v and %v", candidates[0].AdamicAdar, candidates[1].AdamicAdar, adamicAdar, 1/math.Log(5))
+		}
+
+		candidates, err = store.GetSuggestionCandidates(ctx, "a", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(candidates) != 1 || candidates[0].UserId != "x" {
+			t.Fatal("the limit must keep the best candidate")
+		}
+	})
+
+	t.Run("SuggestionCandidatesAfterExpiry", func(t *testing.T) {
+		store, _ := factory(t)
+		connect(t, store, "a", "m")
+		connect(t, store, "m", "x")
+		connect(t, store, "m", "y")
+		request(t, store, "a", "x")
+		request(t, store, "a", "y")
+
+		if _, err := store.ExpirePendingConnections(ctx, time.Now().Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		request(t, store, "a", "y")
+
+		candidates, err := store.GetSuggestionCandidates(ctx, "a", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, candidate := range candidates {
+			got = append(got, candidate.UserId)
+		}
+		assertEqual(t, "candidates after the request to x expired", got, "x")
+	})
+
+	t.Run("PopularUsers", func(t *testing.T) {
+		store, blockStore := factory(t)
+		connect(t, store, "b", "x")
+		connect(t, store, "c", "x")
+		connect(t, store, "d", "x")
+		connect(t, store, "b", "y")
+		connect(t, store, "c", "y")
+		request(t, store, "d", "y")
+		connect(t, store, "a", "z")
+		connect(t, store, "b", "z")
+		connect(t, store, "c", "z")
+		connect(t, store, "d", "z")
+		request(t, store, "a", "w")
+		block(t, blockStore, "v", "a")
+
+		users, err := store.GetPopularUsers(ctx, "a", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, user := range users {
+			got = append(got, fmt.Sprintf("%s:%d", user.UserId, user.FollowerCount))
+		}
+		want := []string{"x:3", "y:2", "b:0"}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+			t.Fatalf("got popular users %v, want %v", got, want)
+		}
+
+		users, err = store.GetMostFollowedUsers(ctx, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = []string{}
+		for _, user := range users {
+			got = append(got, fmt.Sprintf("%s:%d", user.UserId, user.FollowerCount))
+		}
+		want = []string{"z:4", "x:3", "y:2"}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+			t.Fatalf("got most followed users %v, want %v", got, want)
+		}
+
+		excluded, err := store.GetSuggestionExclusions(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "suggestion exclusions of a", sorted(excluded), "v", "w", "z")
+	})
+}
@@ -0,0 +1,120 @@
+package storetest
+
+import (
+	"connection-microservice/model"
+	"context"
+	"testing"
+	"time"
+)
+
+func RunDismissalStoreSuite(t *testing.T, factory DismissalFactory) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	dismiss := func(t *testing.T, store model.DismissalStore, userId string, dismissedUserId string, expiresAt time.Time) {
+		t.Helper()
+		err := store.DismissSuggestion(ctx, model.Dismissal{UserId: userId, DismissedUserId: dismissedUserId, DismissedAt: now, ExpiresAt: expiresAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("DismissedUsersAreNotSuggested", func(t *testing.T) {
+		store, dismissalStore := factory(t)
+		connect(t, store, "a", "b")
+		connect(t, store, "b", "x")
+		connect(t, store, "b", "y")
+		connect(t, store, "b", "z")
+		dismiss(t, dismissalStore, "a", "x", now.Add(time.Hour))
+		dismiss(t, dismissalStore, "a", "y", now.Add(-time.Hour))
+		dismiss(t, dismissalStore, "b", "z", now.Add(time.Hour))
+
+		candidates, err := store.GetSuggestionCandidates(ctx, "a", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, candidate := range candidates {
+			got = append(got, candidate.UserId)
+		}
+		assertEqual(t, "candidates", got, "y", "z")
+
+		popular, err := store.GetPopularUsers(ctx, "a", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = []string{}
+		for _, user := range popular {
+			got = append(got, user.UserId)
+		}
+		assertEqual(t, "popular users", sorted(got), "y", "z")
+
+		excluded, err := store.GetSuggestionExclusions(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "suggestion exclusions", sorted(excluded), "b", "x")
+	})
+
+	t.Run("ListAndUndo", func(t *testing.T) {
+		_, store := factory(t)
+		dismiss(t, store, "a", "z", now.Add(time.Hour))
+		dismiss(t, store, "a", "x", now.Add(time.Hour))
+		dismiss(t, store, "a", "y", now.Add(time.Hour))
+		dismiss(t, store, "a", "w", now.Add(-time.Hour))
+		dismiss(t, store, "b", "x", now.Add(time.Hour))
+
+		page, err := store.GetDismissalsPage(ctx, "a", model.PageRequest{Size: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Dismissals) != 2 || page.Dismissals[0].DismissedUserId != "x" || page.Dismissals[1].DismissedUserId != "y" || page.NextCursor == "" {
+			t.Fatalf("got first page %+v", page)
+		}
+		page, err = store.GetDismissalsPage(ctx, "a", model.PageRequest{Cursor: page.NextCursor, Size: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Dismissals) != 1 || page.Dismissals[0].DismissedUserId != "z" || page.NextCursor != "" {
+			t.Fatalf("got last page %+v", page)
+		}
+
+		undone, err := store.UndoDismissal(ctx, "a", "x")
+		if err != nil || !undone {
+			t.Fatalf("got undone=%v err=%v, want true", undone, err)
+		}
+		undone, err = store.UndoDismissal(ctx, "a", "x")
+		if err != nil || undone {
+			t.Fatalf("got undone=%v err=%v undoing twice, want false", undone, err)
+		}
+		undone, err = store.UndoDismissal(ctx, "a", "w")
+		if err != nil || undone {
+			t.Fatalf("got undone=%v err=%v undoing an expired dismissal, want false", undone, err)
+		}
+
+		userIds, err := store.GetDismissedUserIds(ctx, "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEqual(t, "dismissed users", sorted(userIds), "y", "z")
+	})
+
+	t.Run("DeleteExpiredDismissals", func(t *testing.T) {
+		_, store := factory(t)
+		dismiss(t, store, "a", "x", now.Add(time.Hour))
+		dismiss(t, store, "a", "y", now.Add(-time.Hour))
+		dismiss(t, store, "b", "y", now.Add(-time.Minute))
+
+		deleted, err := store.DeleteExpiredDismissals(ctx, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted != 2 {
+			t.Fatalf("got %d deleted, want 2", deleted)
+		}
+		undone, err := store.UndoDismissal(ctx, "a", "x")
+		if err != nil || !undone {
+			t.Fatal("a dismissal that has not expired must be kept")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package storetest
+
+import (
+	"connection-microservice/model"
+	"context"
+	"testing"
+	"time"
+)
+
+func RunImpressionStoreSuite(t *testing.T, factory ImpressionFactory) {
+	ctx := context.Background()
+	shownAt := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	show := func(t *testing.T, store model.ImpressionStore, shownAt time.Time, variant string, userId string, suggestedUserIds ...string) {
+		t.Helper()
+		var impressions []*model.SuggestionImpression
+		for _, suggestedUserId := range suggestedUserIds {
+			impressions = append(impressions, &model.SuggestionImpression{
+				UserId:          userId,
+				SuggestedUserId: suggestedUserId,
+				Experiment:      "exp",
+				Variant:         variant,
+				LastShownAt:     shownAt,
+			})
+		}
+		if err := store.RecordImpressions(ctx, impressions); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("AcceptLatestImpression", func(t *testing.T) {
+		store := factory(t)
+		show(t, store, shownAt, "a", "u", "x")
+		show(t, store, shownAt.Add(time.Hour), "b", "u", "x")
+
+		accepted, err := store.AcceptImpression(ctx, "u", "x", shownAt, shownAt.Add(2*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accepted == nil || accepted.Variant != "b" || !accepted.AcceptedAt.Equal(shownAt.Add(2*time.Hour)) {
+			t.Fatalf("got %+v, want the impression of variant b accepted", accepted)
+		}
+
+		accepted, err = store.AcceptImpression(ctx, "u", "x", shownAt.Add(time.Minute), shownAt.Add(2*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accepted != nil {
+			t.Fatal("an impression shown before shownAfter must not be accepted")
+		}
+
+		accepted, err = store.AcceptImpression(ctx, "x", "u", shownAt, shownAt.Add(2*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accepted != nil {
+			t.Fatal("impressions are directed")
+		}
+	})
+
+	t.Run("VariantStats", func(t *testing.T) {
+		store := factory(t)
+		show(t, store, shownAt, "a", "u", "x", "y")
+		show(t, store, shownAt.Add(time.Hour), "a", "u", "x", "y")
+		show(t, store, shownAt, "a", "v", "x")
+		show(t, store, shownAt, "b", "u", "z")
+		if _, err := store.AcceptImpression(ctx, "u", "x", shownAt, shownAt.Add(2*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		stats, err := store.GetVariantStats(ctx, "exp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stats) != 2 {
+			t.Fatalf("got %d variants, want 2", len(stats))
+		}
+		if a := stats[0]; a.Variant != "a" || a.Impressions != 5 || a.Suggestions != 3 || a.Acceptances != 1 || a.AcceptanceRate != 1.0/3 {
+			t.Fatalf("got %+v for variant a", a)
+		}
+		if b := stats[1]; b.Variant != "b" || b.Impressions != 1 || b.Suggestions != 1 || b.Acceptances != 0 || b.AcceptanceRate != 0 {
+			t.Fatalf("got %+v for variant b", b)
+		}
+
+		stats, err = store.GetVariantStats(ctx, "other")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(stats) != 0 {
+			t.Fatal("an unknown experiment has no stats")
+		}
+	})
+}
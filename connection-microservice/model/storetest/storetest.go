@@ -0,0 +1,109 @@
+// Package storetest holds the conformance suite every model.ConnectionStore
+// and model.BlockStore backend has to pass. A backend calls RunStoreSuite from
+// its own tests with a factory that returns empty stores sharing one graph:
+//
+//	func TestStores(t *testing.T) {
+//		storetest.RunStoreSuite(t, func(t *testing.T) (model.ConnectionStore, model.BlockStore) {
+//			graph := inmemory.NewGraph()
+//			return inmemory.NewConnectionInMemoryStore(graph), inmemory.NewBlockInMemoryStore(graph)
+//		})
+//	}
+//
+// Impression and dismissal stores run RunImpressionStoreSuite and RunDismissalStoreSuite the same way.
+package storetest
+
+import (
+	"connection-microservice/model"
+	"context"
+	"sort"
+	"testing"
+)
+
+// Factory returns a connection store and a block store backed by the same empty graph.
+type Factory func(t *testing.T) (model.ConnectionStore, model.BlockStore)
+
+// ImpressionFactory returns an impression store backed by an empty graph.
+type ImpressionFactory func(t *testing.T) model.ImpressionStore
+
+// DismissalFactory returns a connection store and a dismissal store backed by the same empty graph.
+type DismissalFactory func(t *testing.T) (model.ConnectionStore, model.DismissalStore)
+
+func RunStoreSuite(t *testing.T, factory Factory) {
+	t.Run("ConnectionStore", func(t *testing.T) {
+		RunConnectionStoreSuite(t, factory)
+	})
+	t.Run("BlockStore", func(t *testing.T) {
+		RunBlockStoreSuite(t, factory)
+	})
+}
+
+func connect(t *testing.T, store model.ConnectionStore, userId string, connectedUserId string) {
+	t.Helper()
+	_, err := store.CreateConnection(context.Background(), &model.Connection{UserId: userId, ConnectedUserId: connectedUserId, IsConnected: true})
+	if err != nil {
+		t.Fatalf("CreateConnection(%s, %s): %v", userId, connectedUserId, err)
+	}
+}
+
+func request(t *testing.T, store model.ConnectionStore, userId string, connectedUserId string) {
+	t.Helper()
+	_, err := store.CreateConnection(context.Background(), &model.Connection{UserId: userId, ConnectedUserId: connectedUserId, PendingConnection: true})
+	if err != nil {
+		t.Fatalf("CreateConnection(%s, %s): %v", userId, connectedUserId, err)
+	}
+}
+
+func block(t *testing.T, store model.BlockStore, userId string, blockedUserId string) {
+	t.Helper()
+	err := store.BlockUser(context.Background(), model.Block{UserId: userId, BlockedUserId: blockedUserId})
+	if err != nil {
+		t.Fatalf("BlockUser(%s, %s): %v", userId, blockedUserId, err)
+	}
+}
+
+// pairs renders connections as sorted "userId->connectedUserId" strings so results can be compared regardless of order.
+func pairs(connections []*model.Connection) []string {
+	retVal := []string{}
+	for _, connection := range connections {
+		retVal = append(retVal, connection.UserId+"->"+connection.ConnectedUserId)
+	}
+	sort.Strings(retVal)
+	return retVal
+}
+
+// blockPairs renders blocks as "userId->blockedUserId" strings, keeping their order.
+func blockPairs(blocks []model.Block) []string {
+	retVal := []string{}
+	for _, block := range blocks {
+		retVal = append(retVal, block.UserId+"->"+block.BlockedUserId)
+	}
+	return retVal
+}
+
+func sorted(ids []string) []string {
+	retVal := append([]string{}, ids...)
+	sort.Strings(retVal)
+	return retVal
+}
+
+func assertEqual(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+	want = sorted(want)
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %v, want %v", what, got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("%s: got %v, want %v", what, got, want)
+		}
+	}
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"math/rand"
+	"sort"
+)
+
+const (
+	// SuggestionReasonFollowedByFollowings means people the user follows follow the suggested user.
+	SuggestionReasonFollowedByFollowings = "FOLLOWED_BY_FOLLOWINGS"
+	// SuggestionReasonPopularInNetwork means the suggested user has many followers and is followed by people the user follows.
+	SuggestionReasonPopularInNetwork = "POPULAR_IN_NETWORK"
+	// SuggestionReasonPopular means the suggested user is one of the most followed users.
+	SuggestionReasonPopular = "POPULAR"
+	// SuggestionReasonRecentlyActive means the suggested user recently followed someone.
+	SuggestionReasonRecentlyActive = "RECENTLY_ACTIVE"
+	// SuggestionReasonSuggestedForYou means the user was picked to fill up the list.
+	SuggestionReasonSuggestedForYou = "SUGGESTED_FOR_YOU"
+)
+
+type Suggestion struct {
+	UserId string
+	Score  float64
+	Reason string
+	// MutualCount is the number of followings behind a FOLLOWED_BY_FOLLOWINGS suggestion.
+	MutualCount int
+	Explanation string
+	// Strategy is the name of the SuggestionStrategy that ranked the suggestion.
+	Strategy string
+}
+
+// SuggestionCandidate is a user followed by MutualCount of the user's followings.
+type SuggestionCandidate struct {
+	UserId        string
+	MutualCount   int
+	FollowerCount int
+	// AdamicAdar sums 1/ln(1+d) over the mutual followings, d being how many users that following follows.
+	AdamicAdar float64
+}
+
+// SampleUserIds shuffles the user ids with a generator seeded by seed and keeps up to limit of them. The same ids
+// and seed always give the same sample, whatever order the ids came in.
+func SampleUserIds(userIds []string, limit int, seed int64) []string {
+	sort.Strings(userIds)
+	rand.New(rand.NewSource(seed)).Shuffle(len(userIds), func(i, j int) {
+		userIds[i], userIds[j] = userIds[j], userIds[i]
+	})
+	if len(userIds) > limit {
+		userIds = userIds[:limit]
+	}
+	return userIds
+}
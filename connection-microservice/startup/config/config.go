@@ -1,12 +1,16 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
 	Port                  string
+	ConnectionDBType      string
 	ConnectionDBURI       string
 	ConnectionDBUsername  string
 	ConnectionDBPassword  string
@@ -14,23 +18,144 @@ type Config struct {
 	ExpiresIn             time.Duration
 	UserServiceHost       string
 	UserServicePort       string
+	DefaultPageSize       int
+	MaxPageSize           int
+	PendingConnectionTTL  time.Duration
+	ExpiryInterval        time.Duration
+	MaxMessageLength      int
+	MutualSampleSize      int
+	MaxDegreeDepth        int
+	SuggestionStrategy    string
+	SuggestionPoolSize    int
+	SuggestionExperiment  string
+	SuggestionVariants    []string
+	AttributionWindow     time.Duration
+	DismissalTTL          time.Duration
+	ColdStartPopular      int
+	ColdStartActive       int
+	ColdStartRandom       int
+	ColdStartSeed         int
+	ColdStartPoolSize     int
+	SuggestionCacheTTL    time.Duration
+	SuggestionRefresh     time.Duration
+	ActiveUserWindow      time.Duration
+	AdminRole             string
+	DrainTimeout          time.Duration
+	HealthCheckInterval   time.Duration
+	HealthCheckTimeout    time.Duration
+	StartupTimeout        time.Duration
+	MetricsPort           string
+	MetricsInterval       time.Duration
 }
 
 func NewConfig() *Config {
 	return &Config{
 		Port:                  getEnv("CONNECTION_SERVICE_PORT", "8087"),
+		ConnectionDBType:      getEnv("CONNECTION_DB_TYPE", "neo4j"),
 		ConnectionDBURI:       getEnv("CONNECTION_DB_URI", "neo4j+s://ac87e36d.databases.neo4j.io"),
 		ConnectionDBUsername:  getEnv("CONNECTION_DB_USERNAME", "neo4j"),
 		ConnectionDBPassword:  getEnv("CONNECTION_DB_PASSWORD", "I7InmmqDyQoT4BhAF5iXOCDB-EQ3wh-hcJn2-8QSobY"),
 		ConnectionServiceName: getEnv("CONNECTION_SERVICE_NAME", "connection_service"),
 		UserServiceHost:       getEnv("USER_SERVICE_HOST", "localhost"),
 		UserServicePort:       getEnv("USER_SERVICE_PORT", "8085"),
+		DefaultPageSize:       getEnvInt("DEFAULT_PAGE_SIZE", 20),
+		MaxPageSize:           getEnvInt("MAX_PAGE_SIZE", 100),
+		PendingConnectionTTL:  getEnvDuration("PENDING_CONNECTION_TTL", 30*24*time.Hour),
+		ExpiryInterval:        getEnvDuration("EXPIRY_INTERVAL", time.Hour),
+		MaxMessageLength:      getEnvInt("MAX_REQUEST_MESSAGE_LENGTH", 300),
+		MutualSampleSize:      getEnvInt("MUTUAL_SAMPLE_SIZE", 3),
+		MaxDegreeDepth:        getEnvInt("MAX_DEGREE_DEPTH", 3),
+		SuggestionStrategy:    getEnv("SUGGESTION_STRATEGY", "common_neighbours"),
+		SuggestionPoolSize:    getEnvInt("SUGGESTION_POOL_SIZE", 200),
+		SuggestionExperiment:  getEnv("SUGGESTION_EXPERIMENT", ""),
+		SuggestionVariants:    getEnvList("SUGGESTION_VARIANTS", nil),
+		AttributionWindow:     getEnvDuration("SUGGESTION_ATTRIBUTION_WINDOW", 7*24*time.Hour),
+		DismissalTTL:          getEnvDuration("SUGGESTION_DISMISSAL_TTL", 90*24*time.Hour),
+		ColdStartPopular:      getEnvInt("COLD_START_POPULAR_WEIGHT", 1),
+		ColdStartActive:       getEnvInt("COLD_START_ACTIVE_WEIGHT", 1),
+		ColdStartRandom:       getEnvInt("COLD_START_RANDOM_WEIGHT", 1),
+		ColdStartSeed:         getEnvInt("COLD_START_SEED", 0),
+		ColdStartPoolSize:     getEnvInt("COLD_START_POOL_SIZE", 200),
+		SuggestionCacheTTL:    getEnvDuration("SUGGESTION_CACHE_TTL", 15*time.Minute),
+		SuggestionRefresh:     getEnvDuration("SUGGESTION_REFRESH_INTERVAL", 5*time.Minute),
+		ActiveUserWindow:      getEnvDuration("ACTIVE_USER_WINDOW", 24*time.Hour),
+		AdminRole:             getEnv("ADMIN_ROLE", "ADMIN"),
+		DrainTimeout:          getEnvDuration("SHUTDOWN_DRAIN_TIMEOUT", 30*time.Second),
+		HealthCheckInterval:   getEnvDuration("HEALTH_CHECK_INTERVAL", 10*time.Second),
+		HealthCheckTimeout:    getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		StartupTimeout:        getEnvDuration("STARTUP_TIMEOUT", time.Minute),
+		MetricsPort:           getEnv("METRICS_PORT", "9090"),
+		MetricsInterval:       getEnvDuration("METRICS_INTERVAL", time.Minute),
 	}
 }
 
+// Validate rejects settings the service can not run with, so a bad environment fails at startup instead of
+// on the first request that uses it.
+func (c *Config) Validate() error {
+	if c.MaxPageSize < 1 {
+		return fmt.Errorf("MAX_PAGE_SIZE must be at least 1, got %d", c.MaxPageSize)
+	}
+	if c.DefaultPageSize < 1 || c.DefaultPageSize > c.MaxPageSize {
+		return fmt.Errorf("DEFAULT_PAGE_SIZE must be between 1 and MAX_PAGE_SIZE (%d), got %d", c.MaxPageSize, c.DefaultPageSize)
+	}
+	// GetDegrees builds a *..MAX_DEGREE_DEPTH path pattern from it
+	if c.MaxDegreeDepth < 1 {
+		return fmt.Errorf("MAX_DEGREE_DEPTH must be at least 1, got %d", c.MaxDegreeDepth)
+	}
+	// background jobs tick at these intervals and time.NewTicker panics on anything but a positive one
+	intervals := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"EXPIRY_INTERVAL", c.ExpiryInterval},
+		{"SUGGESTION_REFRESH_INTERVAL", c.SuggestionRefresh},
+		{"METRICS_INTERVAL", c.MetricsInterval},
+		{"HEALTH_CHECK_INTERVAL", c.HealthCheckInterval},
+	}
+	for _, interval := range intervals {
+		if interval.value <= 0 {
+			return fmt.Errorf("%s must be positive, got %s", interval.name, interval.value)
+		}
+	}
+	return nil
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if number, err := strconv.Atoi(value); err == nil {
+			return number
+		}
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return fallback
+}
+
+// getEnvList splits a comma separated value, skipping empty items.
+func getEnvList(key string, fallback []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *Config)
+		wantErr bool
+	}{
+		{name: "defaults", change: func(c *Config) {}},
+		{name: "zero max page size", change: func(c *Config) { c.MaxPageSize = 0 }, wantErr: true},
+		{name: "zero default page size", change: func(c *Config) { c.DefaultPageSize = 0 }, wantErr: true},
+		{name: "default above max", change: func(c *Config) { c.DefaultPageSize = c.MaxPageSize + 1 }, wantErr: true},
+		{name: "zero degree depth", change: func(c *Config) { c.MaxDegreeDepth = 0 }, wantErr: true},
+		{name: "zero expiry interval", change: func(c *Config) { c.ExpiryInterval = 0 }, wantErr: true},
+		{name: "negative refresh interval", change: func(c *Config) { c.SuggestionRefresh = -time.Minute }, wantErr: true},
+		{name: "zero metrics interval", change: func(c *Config) { c.MetricsInterval = 0 }, wantErr: true},
+		{name: "zero health check interval", change: func(c *Config) { c.HealthCheckInterval = 0 }, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewConfig()
+			test.change(c)
+			if err := c.Validate(); (err != nil) != test.wantErr {
+				t.Fatalf("got %v, want error: %v", err, test.wantErr)
+			}
+		})
+	}
+}
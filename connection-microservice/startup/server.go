@@ -3,19 +3,28 @@ package startup
 import (
 	"connection-microservice/application"
 	"connection-microservice/infrastructure/api"
+	"connection-microservice/infrastructure/inmemory"
 	"connection-microservice/infrastructure/persistance"
 	"connection-microservice/model"
 	"connection-microservice/startup/config"
+	"context"
 	"fmt"
 	connectionService "github.com/XWS-BSEP-TIM1-2022/dislinkt/util/proto/connection"
 	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/token"
 	"github.com/XWS-BSEP-TIM1-2022/dislinkt/util/tracer"
 	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
 	otgo "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"io"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 type Server struct {
@@ -24,6 +33,16 @@ type Server struct {
 	closer      io.Closer
 	jwtManager  *token.JwtManager
 	neo4jDriver neo4j.Driver
+	workers     []worker
+	grpcServer  *grpc.Server
+	serveErrors chan error
+	health      *api.HealthChecker
+	metrics     *http.Server
+}
+
+type worker interface {
+	Start()
+	Stop()
 }
 
 func NewServer(config *config.Config) *Server {
@@ -46,20 +65,99 @@ func (server *Server) GetCloser() io.Closer {
 	return server.closer
 }
 
+// Start opens the gRPC listener with readiness NOT_SERVING, waits for the stores and only then starts the
+// workers and the health checks that report SERVING.
 func (server *Server) Start() {
-	server.neo4jDriver = server.initNeo4jClient()
-	connectionStore := server.initConnectionStore(server.neo4jDriver)
-	blockStore := server.initBlockStore(server.neo4jDriver)
-	blockService := server.initBlockService(blockStore, connectionStore)
-	initConnectionService := server.initConnectionService(connectionStore, blockService)
+	connectionStore, blockStore, impressionStore, dismissalStore := server.initStores()
+	suggestionCache := application.NewSuggestionCache()
+	blockService := server.initBlockService(blockStore, connectionStore, suggestionCache)
+	initConnectionService := server.initConnectionService(connectionStore, impressionStore, dismissalStore, suggestionCache, blockService)
 	connectionHandler := server.initConnectionHandler(initConnectionService, blockService)
+	adminHandler := server.initAdminHandler(server.initAdminService(connectionStore, blockStore, impressionStore, suggestionCache))
+	server.health = api.NewHealthChecker(server.healthChecks(), server.config.HealthCheckInterval, server.config.HealthCheckTimeout)
+
+	server.startMetricsServer()
+	server.startGrpcServer(connectionHandler, adminHandler)
+	server.waitForStores()
 
-	server.startGrpcServer(connectionHandler)
+	server.startWorker(application.NewConnectionExpiryWorker(connectionStore, dismissalStore, suggestionCache, server.config))
+	server.startWorker(application.NewSuggestionRefreshWorker(initConnectionService, suggestionCache, server.config))
+	server.startWorker(application.NewPendingConnectionsMonitor(connectionStore, server.config))
+	server.health.Start()
+}
+
+// WaitForShutdown blocks until the process receives SIGINT or SIGTERM or the gRPC server stops serving.
+func (server *Server) WaitForShutdown() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		log.Println(fmt.Sprintf("received %s", sig))
+	case err := <-server.serveErrors:
+		log.Println(fmt.Sprintf("grpc server stopped serving: %v", err))
+	}
 }
 
+// Stop reports NOT_SERVING, drains the gRPC and metrics servers, then stops the workers, closes the Neo4j driver and flushes the tracer.
 func (server *Server) Stop() {
 	log.Println("stopping server")
 
+	if server.health != nil {
+		server.health.Stop()
+	}
+	server.stopGrpcServer()
+	server.stopMetricsServer()
+	for _, worker := range server.workers {
+		worker.Stop()
+	}
+	if server.neo4jDriver != nil {
+		if err := server.neo4jDriver.Close(); err != nil {
+			log.Println(fmt.Sprintf("failed to close neo4j driver: %s", err))
+		}
+	}
+	if err := server.closer.Close(); err != nil {
+		log.Println(fmt.Sprintf("failed to flush tracer: %s", err))
+	}
+}
+
+// stopGrpcServer lets in-flight RPCs finish for up to config.DrainTimeout and then cancels the rest.
+func (server *Server) stopGrpcServer() {
+	if server.grpcServer == nil {
+		return
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		server.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(server.config.DrainTimeout):
+		log.Println("drain timeout exceeded, cancelling in-flight rpcs")
+		server.grpcServer.Stop()
+		<-stopped
+	}
+}
+
+func (server *Server) startWorker(worker worker) {
+	worker.Start()
+	server.workers = append(server.workers, worker)
+}
+
+func (server *Server) initStores() (model.ConnectionStore, model.BlockStore, model.ImpressionStore, model.DismissalStore) {
+	if server.config.ConnectionDBType == "memory" {
+		log.Println("using in-memory stores")
+		graph := inmemory.NewGraph()
+		return inmemory.NewConnectionInMemoryStore(graph), inmemory.NewBlockInMemoryStore(graph),
+			inmemory.NewImpressionInMemoryStore(graph), inmemory.NewDismissalInMemoryStore(graph)
+	}
+	server.neo4jDriver = server.initNeo4jClient()
+	return server.initConnectionStore(server.neo4jDriver), server.initBlockStore(server.neo4jDriver),
+		server.initImpressionStore(server.neo4jDriver), server.initDismissalStore(server.neo4jDriver)
 }
 
 func (server *Server) initNeo4jClient() neo4j.Driver {
@@ -70,16 +168,99 @@ func (server *Server) initNeo4jClient() neo4j.Driver {
 	return driver
 }
 
-func (server *Server) startGrpcServer(connectionHandler *api.ConnectionHandler) {
+// waitForStores blocks until Neo4j answers and the connection timestamps are backfilled. The in-memory stores
+// are ready right away.
+func (server *Server) waitForStores() {
+	if server.neo4jDriver == nil {
+		return
+	}
+	server.waitForNeo4j(server.neo4jDriver)
+	server.backfillConnectionTimestamps()
+}
+
+// waitForNeo4j retries the connectivity check until the database answers, giving up after config.StartupTimeout.
+func (server *Server) waitForNeo4j(driver neo4j.Driver) {
+	deadline := time.Now().Add(server.config.StartupTimeout)
+	for {
+		err := driver.VerifyConnectivity()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Fatalf("neo4j is not reachable: %s", err)
+		}
+		log.Println(fmt.Sprintf("waiting for neo4j: %s", err))
+		time.Sleep(time.Second)
+	}
+}
+
+func (server *Server) healthChecks() []api.HealthCheck {
+	checks := []api.HealthCheck{{Name: "user-service", Check: server.checkUserService}}
+	if server.neo4jDriver != nil {
+		checks = append(checks, api.HealthCheck{Name: "neo4j", Check: server.neo4jDriver.VerifyConnectivity})
+	}
+	return checks
+}
+
+// checkUserService checks that the user service CreateConnection depends on accepts connections.
+func (server *Server) checkUserService() error {
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%s", server.config.UserServiceHost, server.config.UserServicePort), server.config.HealthCheckTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (server *Server) backfillConnectionTimestamps() {
+	updated, err := persistance.BackfillConnectionTimestamps(server.neo4jDriver)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if updated > 0 {
+		log.Println(fmt.Sprintf("backfilled timestamps on %d connections", updated))
+	}
+}
+
+func (server *Server) startGrpcServer(connectionHandler *api.ConnectionHandler, adminHandler *api.AdminHandler) {
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", server.config.Port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
+	authInterceptor := api.NewAuthInterceptor(server.jwtManager)
+	server.grpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(api.MetricsInterceptor, api.ErrorInterceptor, authInterceptor.Unary, api.ValidationInterceptor))
 	log.Println(fmt.Sprintf("started grpc server on localhost:%s", server.config.Port))
-	connectionService.RegisterConnectionServiceServer(grpcServer, connectionHandler)
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Fatalf("failed to serve: %s", err)
+	connectionService.RegisterConnectionServiceServer(server.grpcServer, connectionHandler)
+	connectionService.RegisterConnectionAdminServiceServer(server.grpcServer, adminHandler)
+	healthpb.RegisterHealthServer(server.grpcServer, server.health.Server())
+
+	server.serveErrors = make(chan error, 1)
+	go func() {
+		server.serveErrors <- server.grpcServer.Serve(listener)
+	}()
+}
+
+func (server *Server) startMetricsServer() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server.metrics = &http.Server{Addr: fmt.Sprintf(":%s", server.config.MetricsPort), Handler: mux}
+
+	go func() {
+		if err := server.metrics.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Println(fmt.Sprintf("failed to serve metrics: %s", err))
+		}
+	}()
+	log.Println(fmt.Sprintf("serving metrics on localhost:%s/metrics", server.config.MetricsPort))
+}
+
+func (server *Server) stopMetricsServer() {
+	if server.metrics == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), server.config.DrainTimeout)
+	defer cancel()
+	if err := server.metrics.Shutdown(ctx); err != nil {
+		log.Println(fmt.Sprintf("failed to stop metrics server: %s", err))
 	}
 }
 
@@ -88,8 +269,8 @@ func (server *Server) initConnectionStore(driver neo4j.Driver) model.ConnectionS
 	return store
 }
 
-func (server *Server) initConnectionService(store model.ConnectionStore, blockService *application.BlockService) *application.ConnectionService {
-	return application.NewConnectionService(store, server.config, blockService)
+func (server *Server) initConnectionService(store model.ConnectionStore, impressionStore model.ImpressionStore, dismissalStore model.DismissalStore, suggestionCache *application.SuggestionCache, blockService *application.BlockService) *application.ConnectionService {
+	return application.NewConnectionService(store, impressionStore, dismissalStore, suggestionCache, server.config, blockService)
 }
 
 func (server *Server) initConnectionHandler(connectionService *application.ConnectionService, blockService *application.BlockService) *api.ConnectionHandler {
@@ -101,6 +282,24 @@ func (server *Server) initBlockStore(driver neo4j.Driver) model.BlockStore {
 	return store
 }
 
-func (server *Server) initBlockService(store model.BlockStore, connectionStore model.ConnectionStore) *application.BlockService {
-	return application.NewBlockService(store, connectionStore, server.config)
+func (server *Server) initImpressionStore(driver neo4j.Driver) model.ImpressionStore {
+	store := persistance.NewImpressionNeo4jStore(driver)
+	return store
+}
+
+func (server *Server) initDismissalStore(driver neo4j.Driver) model.DismissalStore {
+	store := persistance.NewDismissalNeo4jStore(driver)
+	return store
+}
+
+func (server *Server) initBlockService(store model.BlockStore, connectionStore model.ConnectionStore, suggestionCache *application.SuggestionCache) *application.BlockService {
+	return application.NewBlockService(store, connectionStore, suggestionCache, server.config)
+}
+
+func (server *Server) initAdminService(store model.ConnectionStore, blockStore model.BlockStore, impressionStore model.ImpressionStore, suggestionCache *application.SuggestionCache) *application.AdminService {
+	return application.NewAdminService(store, blockStore, impressionStore, suggestionCache, server.config)
+}
+
+func (server *Server) initAdminHandler(adminService *application.AdminService) *api.AdminHandler {
+	return api.NewAdminHandler(adminService)
 }
@@ -0,0 +1,107 @@
+package startup
+
+import (
+	"connection-microservice/startup/config"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"net"
+	"testing"
+	"time"
+)
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// newDrainServer serves the health service on a random port with every RPC taking delay, unless it is
+// cancelled first. The returned channel receives once for every RPC that reaches the server.
+func newDrainServer(t *testing.T, delay time.Duration, drainTimeout time.Duration) (*Server, string, <-chan struct{}) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entered := make(chan struct{}, 1)
+	slow := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		entered <- struct{}{}
+		select {
+		case <-time.After(delay):
+			return handler(ctx, req)
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	server := &Server{
+		config:      &config.Config{DrainTimeout: drainTimeout},
+		closer:      closerFunc(func() error { return nil }),
+		grpcServer:  grpc.NewServer(grpc.UnaryInterceptor(slow)),
+		serveErrors: make(chan error, 1),
+	}
+	healthpb.RegisterHealthServer(server.grpcServer, health.NewServer())
+	go func() {
+		server.serveErrors <- server.grpcServer.Serve(listener)
+	}()
+	return server, listener.Addr().String(), entered
+}
+
+// startCall sends a health check and returns once the server has it in flight.
+func startCall(t *testing.T, address string, entered <-chan struct{}) <-chan error {
+	t.Helper()
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		result <- err
+	}()
+	select {
+	case <-entered:
+	case err := <-result:
+		t.Fatalf("got %v before the rpc reached the server", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("rpc did not reach the server")
+	}
+	return result
+}
+
+func TestStopLetsInFlightRpcFinish(t *testing.T) {
+	server, address, entered := newDrainServer(t, 500*time.Millisecond, 5*time.Second)
+	result := startCall(t, address, entered)
+
+	server.Stop()
+
+	if err := <-result; err != nil {
+		t.Fatalf("got %v, want the in-flight rpc to succeed", err)
+	}
+}
+
+func TestStopCancelsRpcAfterDrainTimeout(t *testing.T) {
+	server, address, entered := newDrainServer(t, time.Minute, 200*time.Millisecond)
+	result := startCall(t, address, entered)
+
+	start := time.Now()
+	server.Stop()
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Stop took %v, want it to give up after the drain timeout", elapsed)
+	}
+
+	err := <-result
+	if err == nil {
+		t.Fatal("got success, want the rpc cancelled")
+	}
+	if code := status.Code(err); code != codes.Unavailable && code != codes.Canceled {
+		t.Fatalf("got %v, want Unavailable or Canceled", err)
+	}
+}